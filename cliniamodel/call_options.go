@@ -0,0 +1,48 @@
+package cliniamodel
+
+import (
+	"context"
+	"maps"
+	"math"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// applyCallOptions applies the per-call options of a model request to its context, ID and parameters. The
+// parameters are copied before being modified. The returned cancel function must be called once the call
+// completes.
+func applyCallOptions(ctx context.Context, opts []common.CallOption, id *string, params *common.Parameters) (context.Context, context.CancelFunc) {
+	if len(opts) == 0 {
+		return ctx, func() {}
+	}
+
+	o := common.NewCallOptions(opts...)
+	if o.RequestID != "" {
+		*id = o.RequestID
+	}
+	if len(o.Metadata) > 0 {
+		ctx = common.ContextWithMetadata(ctx, o.Metadata)
+	}
+
+	if o.Timeout > 0 || o.Priority > 0 {
+		// Copy the parameters to avoid mutating the caller's request.
+		copied := make(common.Parameters, len(*params)+2)
+		maps.Copy(copied, *params)
+		// Triton only accepts int64 timeout and priority parameters.
+		if o.Timeout > 0 {
+			copied.SetInt64(common.ParameterTimeout, o.Timeout.Microseconds())
+		}
+		if o.Priority > 0 {
+			// Priorities beyond the int64 range are clamped to the lowest priority that can be sent.
+			// #nosec G115 -- the priority is clamped to the int64 range.
+			copied.SetInt64(common.ParameterPriority, int64(min(o.Priority, math.MaxInt64)))
+		}
+		*params = copied
+	}
+
+	if o.Timeout > 0 {
+		return context.WithTimeout(ctx, o.Timeout)
+	}
+
+	return ctx, func() {}
+}
@@ -1,19 +1,39 @@
 package cliniamodel
 
-import "context"
+import (
+	"context"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
 
 type Chunker interface {
 	// Chunk returns the chunked results of the given texts.
-	Chunk(ctx context.Context, modelName, modelVersion string, req ChunkRequest) (*ChunkResponse, error)
+	Chunk(ctx context.Context, modelName, modelVersion string, req ChunkRequest, opts ...common.CallOption) (*ChunkResponse, error)
 	// Ready checks if the model is ready to receive requests.
 	Ready(ctx context.Context, modelName, modelVersion string) error
+	// Model returns a handle on the given model version.
+	Model(modelName, modelVersion string) ChunkerModel
+	// Alias returns a handle on the model version of the alias, resolved on every call by the model resolver
+	// of the client options.
+	Alias(alias string) ChunkerModel
+}
+
+// ChunkerModel is a handle on a model of the chunker, bound to a model version or to an alias.
+type ChunkerModel interface {
+	// Chunk returns the chunked results of the given texts.
+	Chunk(ctx context.Context, req ChunkRequest, opts ...common.CallOption) (*ChunkResponse, error)
+	// Ready checks if the model is ready to receive requests.
+	Ready(ctx context.Context) error
 }
 
 type ChunkRequest struct {
-	// ID is the unique identifier for the request.
+	// ID is the unique identifier for the request. If empty, the ID carried by the context (see
+	// common.ContextWithRequestID) is used, or a new one is generated (a random UUIDv4 by default).
 	ID string
 	// Texts is the list of texts to be chunked.
 	Texts []string
+	// Parameters are the optional parameters of the chunker model (e.g. chunk size or overlap).
+	Parameters common.Parameters
 }
 
 type ChunkResponse struct {
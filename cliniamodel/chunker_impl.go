@@ -7,6 +7,7 @@ import (
 
 	"github.com/clinia/models-client-go/cliniamodel/common"
 	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -14,12 +15,22 @@ const (
 	chunkerOutputKey     string = "chunk"
 	chunkerInputDatatype        = datatype.Bytes
 	// Pad key is used to pad the ouput when the number of chunks across inputs is not the same.
-	chunkerOutputPadKey = "pad"
+	chunkerOutputPadKey = common.OutputPadValue
 )
 
+// chunkerTensors are the tensors of the chunker models.
+var chunkerTensors = clientTensors{
+	inputs:  []tensor{{name: chunkerInputKey, datatype: chunkerInputDatatype}},
+	outputs: []tensor{{name: chunkerOutputKey, datatype: datatype.Bytes}},
+}
+
 // chunker is a struct that implements the Chunker interface.
 type chunker struct {
-	requester common.Requester
+	requester  common.Requester
+	tracer     trace.Tracer
+	requestIDs common.RequestIDGenerator
+	models     common.ModelResolver
+	tensors    *tensorResolver
 }
 
 var _ Chunker = (*chunker)(nil)
@@ -27,20 +38,45 @@ var _ Chunker = (*chunker)(nil)
 // NewChunker creates a new chunker instance.
 func NewChunker(ctx context.Context, opts common.ClientOptions) Chunker {
 	return &chunker{
-		requester: opts.Requester,
+		requester:  opts.Requester,
+		tracer:     common.Tracer(opts.TracerProvider),
+		requestIDs: opts.RequestIDGenerator,
+		models:     opts.ModelResolver,
+		tensors:    newTensorResolver(opts.Requester, opts.TensorMapping, opts.Logger),
 	}
 }
 
 // Chunk implements the Chunker interface. It takes a context, model name, model version, and a ChunkRequest as input,
 // and returns a ChunkResponse or an error.
-func (c *chunker) Chunk(ctx context.Context, modelName string, modelVersion string, req ChunkRequest) (*ChunkResponse, error) {
+func (c *chunker) Chunk(ctx context.Context, modelName string, modelVersion string, req ChunkRequest, opts ...common.CallOption) (*ChunkResponse, error) {
+	ctx, cancel := applyCallOptions(ctx, opts, &req.ID, &req.Parameters)
+	defer cancel()
+
+	req.ID = common.RequestID(ctx, req.ID, c.requestIDs)
+	ctx = common.ContextWithRequestID(ctx, req.ID)
+	ctx, span := common.StartModelSpan(ctx, c.tracer, "Chunker.Chunk", modelName, modelVersion, len(req.Texts),
+		trace.WithAttributes(common.AttributeRequestID.String(req.ID)))
+	res, err := c.chunk(ctx, modelName, modelVersion, req)
+	common.EndSpan(span, err)
+
+	return res, err
+}
+
+func (c *chunker) chunk(ctx context.Context, modelName, modelVersion string, req ChunkRequest) (*ChunkResponse, error) {
 	if len(req.Texts) == 0 {
 		return nil, errors.New("texts cannot be empty")
 	}
+
+	// Resolve the tensor names of the model.
+	tensors, err := c.tensors.resolve(ctx, modelName, modelVersion, chunkerTensors, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare the inputs.
 	inputs := []common.Input{
 		{
-			Name:     chunkerInputKey,
+			Name:     tensors.name(chunkerInputKey),
 			Shape:    []int64{int64(len(req.Texts)), 1},
 			Datatype: chunkerInputDatatype,
 			Content: common.Content{
@@ -49,7 +85,7 @@ func (c *chunker) Chunk(ctx context.Context, modelName string, modelVersion stri
 		},
 	}
 
-	outputKeys := []string{chunkerOutputKey}
+	outputKeys := tensors.outputKeys(chunkerOutputKey)
 
 	res, err := c.requester.Infer(ctx, common.InferRequest{
 		ID:           req.ID,
@@ -57,14 +93,23 @@ func (c *chunker) Chunk(ctx context.Context, modelName string, modelVersion stri
 		ModelVersion: modelVersion,
 		Inputs:       inputs,
 		OutputKeys:   outputKeys,
+		Parameters:   req.Parameters,
 	})
+	if err != nil {
+		c.tensors.invalidateOnError(modelName, modelVersion, err)
+		return nil, err
+	}
+
+	output, err := res.Output(tensors.name(chunkerOutputKey))
+	if err != nil {
+		return nil, err
+	}
+
+	outputStringContents, err := output.StringMatrixContent()
 	if err != nil {
 		return nil, err
 	}
 
-	// Since we have only one output, we can directly access the first output.
-	// This is validated in the infer function and will be returned as an error if the output is not of the expected size.
-	outputStringContents, err := res.Outputs[0].StringMatrixContent()
 	chunks := [][]Chunk{}
 	// We loop over the string contents and unmarshal them into the Chunk struct.
 	for _, outputStringContent := range outputStringContents {
@@ -92,5 +137,44 @@ func (c *chunker) Chunk(ctx context.Context, modelName string, modelVersion stri
 
 // Ready implements the Chunker interface. It checks the readiness status of the model.
 func (c *chunker) Ready(ctx context.Context, modelName string, modelVersion string) error {
-	return c.requester.Ready(ctx, modelName, modelVersion)
+	err := c.requester.Ready(ctx, modelName, modelVersion)
+	if err != nil {
+		// The model may be reloaded with other tensors before it is ready again.
+		c.tensors.invalidate(modelName, modelVersion)
+	}
+
+	return err
+}
+
+// Model implements the Chunker interface.
+func (c *chunker) Model(modelName, modelVersion string) ChunkerModel {
+	return &chunkerModel{chunker: c, model: fixedModel(modelName, modelVersion)}
+}
+
+// Alias implements the Chunker interface.
+func (c *chunker) Alias(alias string) ChunkerModel {
+	return &chunkerModel{chunker: c, model: aliasedModel(c.models, alias)}
+}
+
+// chunkerModel is a handle on a model of the chunker.
+type chunkerModel struct {
+	chunker *chunker
+	model   modelBinding
+}
+
+var _ ChunkerModel = (*chunkerModel)(nil)
+
+// Chunk implements the ChunkerModel interface.
+func (m *chunkerModel) Chunk(ctx context.Context, req ChunkRequest, opts ...common.CallOption) (*ChunkResponse, error) {
+	ref, err := m.model.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.chunker.Chunk(ctx, ref.Name, ref.Version, req, opts...)
+}
+
+// Ready implements the ChunkerModel interface. The handles on a routed alias check every version of the route.
+func (m *chunkerModel) Ready(ctx context.Context) error {
+	return m.model.ready(ctx, m.chunker.Ready)
 }
@@ -0,0 +1,115 @@
+package cliniamodel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/clinia/models-client-go/cliniamodel/requestergrpc"
+	"github.com/clinia/models-client-go/cliniamodel/requesterhttp/filesvcclient"
+)
+
+// Config configures a Client.
+type Config struct {
+	// Requester configures the gRPC requester of the model server. It is ignored if a requester is provided
+	// with common.WithRequester.
+	Requester common.RequesterConfig
+	// RequesterOptions configure the gRPC transport of the requester.
+	RequesterOptions []requestergrpc.Option
+	// FileServiceURL is the base URL of the file service. The client has no file processor if empty.
+	FileServiceURL string
+	// FileServiceOptions configure the HTTP client of the file service.
+	FileServiceOptions []filesvcclient.ClientOption
+}
+
+// Client gives access to all the model clients and to the file processor, sharing a single requester.
+type Client struct {
+	requester     common.Requester
+	ownsRequester bool
+
+	embedder       Embedder
+	sparseEmbedder SparseEmbedder
+	ranker         Ranker
+	chunker        Chunker
+	fileProcessor  FileProcessor
+}
+
+// NewClient creates the requester of the model server and the model clients sharing it. The options apply
+// to all the model clients. If a requester is provided with common.WithRequester, it is used as is and the
+// caller remains responsible for closing it. The model clients log to the logger of the requester configuration
+// unless one is provided with common.WithLogger.
+func NewClient(ctx context.Context, cfg Config, opts ...common.ClientOption) (*Client, error) {
+	o := common.ClientOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.Logger == nil {
+		o.Logger = cfg.Requester.Logger
+	}
+
+	c := &Client{}
+	if o.Requester == nil {
+		requester, err := requestergrpc.NewRequester(ctx, cfg.Requester, cfg.RequesterOptions...)
+		if err != nil {
+			return nil, err
+		}
+		o.Requester = requester
+		c.ownsRequester = true
+	}
+	c.requester = o.Requester
+
+	if cfg.FileServiceURL != "" {
+		fileProcessor, err := NewFileProcessor(cfg.FileServiceURL, cfg.FileServiceOptions...)
+		if err != nil {
+			return nil, errors.Join(err, c.Close())
+		}
+		c.fileProcessor = fileProcessor
+	}
+
+	c.embedder = NewEmbedder(ctx, o)
+	c.sparseEmbedder = NewSparseEmbedder(ctx, o)
+	c.ranker = NewRanker(o)
+	c.chunker = NewChunker(ctx, o)
+
+	return c, nil
+}
+
+// Requester returns the requester shared by the model clients.
+func (c *Client) Requester() common.Requester {
+	return c.requester
+}
+
+// Embedder returns the embedder client.
+func (c *Client) Embedder() Embedder {
+	return c.embedder
+}
+
+// SparseEmbedder returns the sparse embedder client.
+func (c *Client) SparseEmbedder() SparseEmbedder {
+	return c.sparseEmbedder
+}
+
+// Ranker returns the ranker client.
+func (c *Client) Ranker() Ranker {
+	return c.ranker
+}
+
+// Chunker returns the chunker client.
+func (c *Client) Chunker() Chunker {
+	return c.chunker
+}
+
+// FileProcessor returns the file processor, or nil if the client was created without a file service URL.
+func (c *Client) FileProcessor() FileProcessor {
+	return c.fileProcessor
+}
+
+// Close closes the requester created by the client. A requester provided with common.WithRequester is left open.
+func (c *Client) Close() error {
+	if !c.ownsRequester {
+		return nil
+	}
+
+	return c.requester.Close()
+}
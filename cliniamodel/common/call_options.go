@@ -0,0 +1,89 @@
+package common
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"time"
+)
+
+// CallOptions are the options of a single call to a model client or to the file processor.
+type CallOptions struct {
+	// Timeout bounds the duration of the call. For model requests, it is also sent to the model server as
+	// the timeout parameter, so that requests that waited too long in the scheduling queue are dropped.
+	Timeout time.Duration
+	// Priority is the priority of the request in the scheduling queue of the model server, 1 being the
+	// highest priority. The model default is used if 0. It is ignored by the file processor.
+	Priority uint64
+	// Metadata is sent as gRPC metadata with model requests, and as HTTP headers with file service requests.
+	Metadata map[string]string
+	// RequestID is the ID of the request. It takes precedence over the ID of the request struct.
+	RequestID string
+	// RequestEditors edit the HTTP requests sent to the file service. They are ignored by the model clients.
+	RequestEditors []func(ctx context.Context, req *http.Request) error
+}
+
+// CallOption configures a single call.
+type CallOption func(*CallOptions)
+
+// NewCallOptions applies the options in order.
+func NewCallOptions(opts ...CallOption) CallOptions {
+	o := CallOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithTimeout bounds the duration of the call.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithPriority sets the priority of the request in the scheduling queue of the model server, 1 being the
+// highest priority.
+func WithPriority(priority uint64) CallOption {
+	return func(o *CallOptions) {
+		o.Priority = priority
+	}
+}
+
+// WithMetadata adds a metadata entry to the call, sent as gRPC metadata or as an HTTP header.
+func WithMetadata(key, value string) CallOption {
+	return func(o *CallOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		o.Metadata[key] = value
+	}
+}
+
+// WithRequestID sets the ID of the request.
+func WithRequestID(id string) CallOption {
+	return func(o *CallOptions) {
+		o.RequestID = id
+	}
+}
+
+type metadataKey struct{}
+
+// ContextWithMetadata returns a copy of ctx carrying the given metadata, in addition to the metadata
+// already carried by ctx. The requesters send it along with their requests.
+func ContextWithMetadata(ctx context.Context, md map[string]string) context.Context {
+	merged := maps.Clone(MetadataFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(md))
+	}
+	maps.Copy(merged, md)
+
+	return context.WithValue(ctx, metadataKey{}, merged)
+}
+
+// MetadataFromContext returns the metadata carried by ctx, if any. The returned map must not be modified.
+func MetadataFromContext(ctx context.Context) map[string]string {
+	md, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return md
+}
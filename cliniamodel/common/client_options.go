@@ -1,8 +1,25 @@
 package common
 
+import (
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 type ClientOptions struct {
 	Requester Requester
+	// TracerProvider is used to create the spans of the model clients. Defaults to the global tracer provider.
+	TracerProvider trace.TracerProvider
+	// RequestIDGenerator generates the IDs of the requests made without one, when the context does not
+	// carry one either. Defaults to UUIDRequestIDGenerator.
+	RequestIDGenerator RequestIDGenerator
+	// ModelResolver resolves the model aliases of the handles returned by the Alias methods of the model clients.
+	ModelResolver ModelResolver
+	// TensorMapping maps the tensor names of the models. The default tensor names are used if empty.
+	TensorMapping TensorMapping
+	// Logger receives the structured events of the model clients, such as the model metadata that cannot be
+	// fetched. Nothing is logged if nil.
+	Logger *slog.Logger
 }
 
 type ClientOption func(*ClientOptions)
@@ -12,3 +29,38 @@ func WithRequester(requester Requester) func(*ClientOptions) {
 		o.Requester = requester
 	}
 }
+
+// WithTracerProvider sets the tracer provider used to create the spans of the model clients.
+func WithTracerProvider(tp trace.TracerProvider) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.TracerProvider = tp
+	}
+}
+
+// WithRequestIDGenerator sets the generator of the IDs of the requests made without one.
+func WithRequestIDGenerator(generator RequestIDGenerator) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.RequestIDGenerator = generator
+	}
+}
+
+// WithModelResolver sets the resolver of the model aliases.
+func WithModelResolver(resolver ModelResolver) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.ModelResolver = resolver
+	}
+}
+
+// WithTensorMapping sets the mapping of the tensor names of the models.
+func WithTensorMapping(mapping TensorMapping) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.TensorMapping = mapping
+	}
+}
+
+// WithLogger sets the logger of the model clients.
+func WithLogger(logger *slog.Logger) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.Logger = logger
+	}
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OutputMismatchError is returned when the outputs of an inference response do not match the requested outputs.
+type OutputMismatchError struct {
+	// Missing lists the requested outputs that are absent from the response.
+	Missing []string
+	// Unexpected lists the outputs of the response that were not requested.
+	Unexpected []string
+}
+
+func (e *OutputMismatchError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing outputs [%s]", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Unexpected) > 0 {
+		parts = append(parts, fmt.Sprintf("unexpected outputs [%s]", strings.Join(e.Unexpected, ", ")))
+	}
+
+	return "output mismatch: " + strings.Join(parts, ", ")
+}
+
+// PayloadTooLargeError is returned when a single element of a request exceeds the request size budget,
+// so the request cannot be split to fit in the budget.
+type PayloadTooLargeError struct {
+	// Index is the index of the element in the batch.
+	Index int
+	// Size is the serialized size of the element in bytes.
+	Size int
+	// Limit is the request size budget in bytes.
+	Limit int
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("element %d of %d bytes exceeds the request size budget of %d bytes", e.Index, e.Size, e.Limit)
+}
+
+// Causes of a TensorDecodeError.
+var (
+	// ErrTruncatedTensor is returned when a tensor ends in the middle of an element or of its length prefix.
+	ErrTruncatedTensor = errors.New("truncated tensor")
+	// ErrElementTooLarge is returned when an element of a tensor exceeds the element size limit.
+	ErrElementTooLarge = errors.New("element exceeds the size limit")
+	// ErrElementCount is returned when the number of elements of a tensor does not match its shape.
+	ErrElementCount = errors.New("element count does not match the shape")
+	// ErrInvalidBool is returned when an element of a BOOL tensor is neither 0 nor 1.
+	ErrInvalidBool = errors.New("invalid bool element")
+)
+
+// TensorDecodeError is returned when an output tensor of an inference response cannot be decoded.
+type TensorDecodeError struct {
+	// Tensor is the name of the output tensor.
+	Tensor string
+	// Index is the index of the element that could not be decoded, or -1 if the error concerns the whole tensor.
+	Index int
+	// Err is the cause of the error, one of ErrTruncatedTensor, ErrElementTooLarge, ErrElementCount or ErrInvalidBool.
+	Err error
+}
+
+func (e *TensorDecodeError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("decode tensor %q: %v", e.Tensor, e.Err)
+	}
+
+	return fmt.Sprintf("decode tensor %q: element %d: %v", e.Tensor, e.Index, e.Err)
+}
+
+func (e *TensorDecodeError) Unwrap() error {
+	return e.Err
+}
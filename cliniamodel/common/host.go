@@ -1,31 +1,181 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 type HostScheme string
 
 const (
+	// HTTP is a host reached without transport security.
 	HTTP HostScheme = "http"
+	// HTTPS is a host reached with TLS.
 	HTTPS HostScheme = "https"
+	// Unix is a Unix domain socket, reached without transport security. The Url of the host is the socket path.
+	Unix HostScheme = "unix"
 )
 
 type Host struct {
-	Url string
-	Port int
+	Url    string
+	Port   int
 	Scheme HostScheme
+	// Resolver is the gRPC name resolver used to resolve the host (e.g. "dns" to balance over multiple A records).
+	// The gRPC default resolver is used if empty.
+	Resolver string
+	// Path is the base path of HTTP services (e.g. "/filesvc"). It is ignored by gRPC.
+	Path string
 }
 
-// String returns the host in the format of "scheme://url:port"
+// ParseHost parses a host URL. The supported formats are:
+//   - grpc://host:port and http://host:port for hosts reached without transport security
+//   - grpcs://host:port and https://host:port for hosts reached with TLS
+//   - unix:///path/to/socket for Unix domain sockets
+//   - dns:///host:port and grpc+dns:///host:port for hosts resolved with the gRPC DNS resolver, reached without
+//     transport security
+//   - grpcs+dns:///host:port for hosts resolved with the gRPC DNS resolver, reached with TLS
+//
+// IPv6 literals must be enclosed in brackets (e.g. grpc://[::1]:8001). The port defaults to 80 and 443
+// for http and https and is mandatory otherwise.
+func ParseHost(rawURL string) (Host, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Host{}, fmt.Errorf("invalid host %q: %w", rawURL, err)
+	}
+
+	var host Host
+	switch u.Scheme {
+	case "unix":
+		path := u.Path
+		if path == "" {
+			path = u.Opaque
+		}
+		host = Host{Url: path, Scheme: Unix}
+	case "dns", "grpc+dns", "grpcs+dns":
+		if u.Host != "" {
+			return Host{}, fmt.Errorf("invalid host %q: dns authorities are not supported", rawURL)
+		}
+		scheme := HTTP
+		if u.Scheme == "grpcs+dns" {
+			scheme = HTTPS
+		}
+		host, err = parseHostPort(strings.TrimPrefix(u.Path, "/"), scheme, 0)
+		host.Resolver = "dns"
+	case "grpc":
+		host, err = parseHostPort(u.Host, HTTP, 0)
+	case "grpcs":
+		host, err = parseHostPort(u.Host, HTTPS, 0)
+	case "http":
+		host, err = parseHostPort(u.Host, HTTP, 80)
+		host.Path = strings.TrimSuffix(u.Path, "/")
+	case "https":
+		host, err = parseHostPort(u.Host, HTTPS, 443)
+		host.Path = strings.TrimSuffix(u.Path, "/")
+	case "":
+		return Host{}, fmt.Errorf("invalid host %q: missing scheme", rawURL)
+	default:
+		return Host{}, fmt.Errorf("invalid host %q: unsupported scheme %q", rawURL, u.Scheme)
+	}
+	if err != nil {
+		return Host{}, fmt.Errorf("invalid host %q: %w", rawURL, err)
+	}
+
+	if err := host.Validate(); err != nil {
+		return Host{}, fmt.Errorf("invalid host %q: %w", rawURL, err)
+	}
+
+	return host, nil
+}
+
+// parseHostPort parses a "host:port" pair, using the default port if none is given and defaultPort is not 0.
+func parseHostPort(hostPort string, scheme HostScheme, defaultPort int) (Host, error) {
+	// The port follows the last colon, unless that colon is part of a bracketed IPv6 literal.
+	hasPort := strings.LastIndex(hostPort, ":") > strings.LastIndex(hostPort, "]")
+	if !hasPort && defaultPort != 0 {
+		hostPort = net.JoinHostPort(strings.Trim(hostPort, "[]"), strconv.Itoa(defaultPort))
+	}
+
+	hostname, portStr, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return Host{}, err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return Host{}, fmt.Errorf("invalid port %q", portStr)
+	}
+
+	return Host{Url: hostname, Port: port, Scheme: scheme}, nil
+}
+
+// Validate checks that the host can be connected to.
+func (h Host) Validate() error {
+	switch h.Scheme {
+	case Unix:
+		if h.Url == "" {
+			return errors.New("missing socket path")
+		}
+		return nil
+	case HTTP, HTTPS:
+	default:
+		return fmt.Errorf("unsupported scheme %q", h.Scheme)
+	}
+
+	if h.Url == "" {
+		return errors.New("missing hostname")
+	}
+	if h.Port <= 0 || h.Port > 65535 {
+		return fmt.Errorf("invalid port %d", h.Port)
+	}
+
+	return nil
+}
+
+// String returns the host in the format of "scheme://url:port", or "unix:///path" for Unix domain sockets.
 func (h Host) String() string {
-	return fmt.Sprintf("%s://%s:%d", h.Scheme, h.Url, h.Port)
+	if h.Scheme == Unix {
+		return unixURL(h.Url)
+	}
+
+	return fmt.Sprintf("%s://%s%s", h.Scheme, h.Host(), h.Path)
 }
 
-// Host returns the host in the format of "url:port"
+// Host returns the host in the format of "url:port", or the socket path for Unix domain sockets.
+// IPv6 literals are enclosed in brackets.
 func (h Host) Host() string {
-	return fmt.Sprintf("%s:%d", h.Url, h.Port)
+	if h.Scheme == Unix {
+		return h.Url
+	}
+
+	return net.JoinHostPort(h.Url, strconv.Itoa(h.Port))
 }
 
+// Target returns the gRPC target of the host.
+func (h Host) Target() string {
+	switch {
+	case h.Scheme == Unix:
+		return unixURL(h.Url)
+	case h.Resolver != "":
+		return fmt.Sprintf("%s:///%s", h.Resolver, h.Host())
+	default:
+		return h.Host()
+	}
+}
 
+// Secure returns whether the host is reached with TLS.
+func (h Host) Secure() bool {
+	return h.Scheme == HTTPS
+}
 
+// unixURL returns the URL of a Unix domain socket, in the "unix:///absolute/path" or "unix:relative/path" format.
+func unixURL(path string) string {
+	if strings.HasPrefix(path, "/") {
+		return "unix://" + path
+	}
+
+	return "unix:" + path
+}
@@ -0,0 +1,37 @@
+package common
+
+import "github.com/clinia/models-client-go/cliniamodel/datatype"
+
+// TensorMetadata describes an input or output tensor of a model.
+type TensorMetadata struct {
+	Name     string
+	Datatype datatype.Datatype
+	// Shape is the shape of the tensor. Variable-size dimensions are reported as -1.
+	Shape []int64
+}
+
+// ModelMetadata describes a model version as reported by the model server.
+type ModelMetadata struct {
+	// ModelName is the name of the model.
+	ModelName string
+	// ModelVersion is the version of the model.
+	ModelVersion string
+	// Platform is the framework or backend serving the model.
+	Platform string
+	// Inputs describes the input tensors of the model.
+	Inputs []TensorMetadata
+	// Outputs describes the output tensors of the model.
+	Outputs []TensorMetadata
+}
+
+// ModelIndex is an entry of the model repository.
+type ModelIndex struct {
+	// ModelName is the name of the model.
+	ModelName string
+	// ModelVersion is the version of the model. It can be empty if the model is not loaded.
+	ModelVersion string
+	// State is the state of the model version (e.g. "READY", "UNAVAILABLE").
+	State string
+	// Reason is the reason of the state, if any.
+	Reason string
+}
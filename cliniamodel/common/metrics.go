@@ -0,0 +1,64 @@
+package common
+
+import "time"
+
+// Operations recorded by the requesters.
+const (
+	OperationInfer = "infer"
+)
+
+// RequestObservation describes a completed request.
+type RequestObservation struct {
+	// Operation is the operation performed by the request (e.g. OperationInfer).
+	Operation string
+	// ModelName is the name of the model the request was made to, if any.
+	ModelName string
+	// ModelVersion is the version of the model the request was made to, if any.
+	ModelVersion string
+	// Outcome is the outcome of the request, either OutcomeSuccess or OutcomeError.
+	Outcome string
+	// Duration is the time taken by the request.
+	Duration time.Duration
+	// BatchSize is the number of elements sent in the request. It is 0 when not applicable.
+	BatchSize int
+	// PayloadBytes is the size of the payload sent in the request. It is 0 when unknown.
+	PayloadBytes int
+}
+
+// MetricsRecorder records the metrics of the inference traffic.
+type MetricsRecorder interface {
+	// ObserveRequest records a completed request.
+	ObserveRequest(obs RequestObservation)
+	// AddInFlight adds delta to the number of requests in flight for the given operation and model version.
+	AddInFlight(operation, modelName, modelVersion string, delta int)
+	// ObserveRetry records a retry of a request for the given operation and model version.
+	ObserveRetry(operation, modelName, modelVersion string)
+	// ObserveRoute records a request on the given model alias routed to the given model version.
+	ObserveRoute(alias, modelName, modelVersion string)
+}
+
+// NoopMetricsRecorder is a MetricsRecorder that discards all the metrics.
+type NoopMetricsRecorder struct{}
+
+var _ MetricsRecorder = NoopMetricsRecorder{}
+
+// ObserveRequest implements MetricsRecorder.
+func (NoopMetricsRecorder) ObserveRequest(RequestObservation) {}
+
+// AddInFlight implements MetricsRecorder.
+func (NoopMetricsRecorder) AddInFlight(string, string, string, int) {}
+
+// ObserveRetry implements MetricsRecorder.
+func (NoopMetricsRecorder) ObserveRetry(string, string, string) {}
+
+// ObserveRoute implements MetricsRecorder.
+func (NoopMetricsRecorder) ObserveRoute(string, string, string) {}
+
+// Outcome returns the outcome of an operation given its error.
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+
+	return OutcomeSuccess
+}
@@ -0,0 +1,46 @@
+package common
+
+import "context"
+
+// Middleware wraps a Requester to add a cross-cutting behavior (e.g. authentication, caching, retries).
+type Middleware func(next Requester) Requester
+
+// Chain wraps the requester with the given middlewares. The first middleware is the outermost one,
+// meaning it is the first to see the requests and the last to see the responses.
+func Chain(requester Requester, middlewares ...Middleware) Requester {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		requester = middlewares[i](requester)
+	}
+
+	return requester
+}
+
+// InferFunc performs an inference request.
+type InferFunc func(ctx context.Context, req InferRequest) (*InferResponse, error)
+
+// InferInterceptor intercepts an inference request. It must call next to carry on with the request.
+type InferInterceptor func(ctx context.Context, req InferRequest, next InferFunc) (*InferResponse, error)
+
+// InterceptInfer returns a middleware applying the interceptor to the inference requests.
+// The other calls are passed through to the wrapped requester.
+func InterceptInfer(interceptor InferInterceptor) Middleware {
+	return func(next Requester) Requester {
+		return &interceptedRequester{
+			Requester:   next,
+			interceptor: interceptor,
+		}
+	}
+}
+
+// interceptedRequester is a Requester whose inference requests go through an interceptor.
+type interceptedRequester struct {
+	Requester
+	interceptor InferInterceptor
+}
+
+var _ Requester = (*interceptedRequester)(nil)
+
+// Infer implements Requester.
+func (r *interceptedRequester) Infer(ctx context.Context, req InferRequest) (*InferResponse, error) {
+	return r.interceptor(ctx, req, r.Requester.Infer)
+}
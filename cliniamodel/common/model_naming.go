@@ -0,0 +1,70 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModelNaming maps the model name and version used by the clients to the model name and version
+// known by the model server.
+type ModelNaming interface {
+	// Format returns the server-side model name and version for the given model name and version.
+	Format(modelName, modelVersion string) (string, string)
+	// Parse recovers the model name and version from the server-side model name and version.
+	Parse(serverModelName, serverModelVersion string) (string, string)
+}
+
+// ComposedModelNaming is the naming scheme used by Clinia's deployments. The model version is part
+// of the server-side model name, formatted as "name:version", and the server-side version is always 1
+// because all models deployed within the same Triton server instance -- when stored in different
+// model repositories -- must have unique names.
+type ComposedModelNaming struct{}
+
+var _ ModelNaming = ComposedModelNaming{}
+
+// Format implements ModelNaming.
+func (ComposedModelNaming) Format(modelName, modelVersion string) (string, string) {
+	return fmt.Sprintf("%s:%s", modelName, modelVersion), "1"
+}
+
+// Parse implements ModelNaming. Server-side names that are not composed are returned untouched.
+func (ComposedModelNaming) Parse(serverModelName, serverModelVersion string) (string, string) {
+	i := strings.LastIndex(serverModelName, ":")
+	if i < 0 {
+		return serverModelName, serverModelVersion
+	}
+
+	return serverModelName[:i], serverModelName[i+1:]
+}
+
+// NativeModelNaming is the naming scheme of stock Triton deployments, where the model versions are
+// the numbered version directories of the model repository.
+type NativeModelNaming struct{}
+
+var _ ModelNaming = NativeModelNaming{}
+
+// Format implements ModelNaming.
+func (NativeModelNaming) Format(modelName, modelVersion string) (string, string) {
+	return modelName, modelVersion
+}
+
+// Parse implements ModelNaming.
+func (NativeModelNaming) Parse(serverModelName, serverModelVersion string) (string, string) {
+	return serverModelName, serverModelVersion
+}
+
+// ModelNamingFunc is a custom naming scheme. Since the function cannot be inverted, the server-side
+// model name and version are reported untouched.
+type ModelNamingFunc func(modelName, modelVersion string) (string, string)
+
+var _ ModelNaming = ModelNamingFunc(nil)
+
+// Format implements ModelNaming.
+func (f ModelNamingFunc) Format(modelName, modelVersion string) (string, string) {
+	return f(modelName, modelVersion)
+}
+
+// Parse implements ModelNaming.
+func (f ModelNamingFunc) Parse(serverModelName, serverModelVersion string) (string, string) {
+	return serverModelName, serverModelVersion
+}
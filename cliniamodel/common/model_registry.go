@@ -0,0 +1,122 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnknownModelAlias is returned when a model alias is not registered.
+var ErrUnknownModelAlias = errors.New("unknown model alias")
+
+// ModelRef identifies a model version.
+type ModelRef struct {
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+	// Version is the version of the model.
+	Version string `json:"version" yaml:"version"`
+}
+
+func (r ModelRef) String() string {
+	return r.Name + ":" + r.Version
+}
+
+// ModelResolver resolves logical model aliases (e.g. "query-embedder") to model versions.
+// Aliases are resolved on every call, so that the model version behind an alias can change at runtime.
+type ModelResolver interface {
+	Resolve(ctx context.Context, alias string) (ModelRef, error)
+}
+
+// VersionsResolver is implemented by the model resolvers that can resolve an alias to several model versions
+// (e.g. Router), so that readiness checks cover all of them.
+type VersionsResolver interface {
+	// ResolveVersions returns every model version the alias may resolve to, without resolving a request.
+	ResolveVersions(ctx context.Context, alias string) ([]ModelRef, error)
+}
+
+// ResolveVersions returns every model version the alias may resolve to with the resolver. Resolvers that do not
+// implement VersionsResolver resolve the alias to a single model version.
+func ResolveVersions(ctx context.Context, resolver ModelResolver, alias string) ([]ModelRef, error) {
+	if r, ok := resolver.(VersionsResolver); ok {
+		return r.ResolveVersions(ctx, alias)
+	}
+
+	ref, err := resolver.Resolve(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	return []ModelRef{ref}, nil
+}
+
+// ModelResolverFunc is a function implementing ModelResolver.
+type ModelResolverFunc func(ctx context.Context, alias string) (ModelRef, error)
+
+// Resolve implements ModelResolver.
+func (f ModelResolverFunc) Resolve(ctx context.Context, alias string) (ModelRef, error) {
+	return f(ctx, alias)
+}
+
+// ModelRegistry is a ModelResolver backed by a map of aliases, typically loaded from configuration.
+// It is safe for concurrent use.
+type ModelRegistry struct {
+	mu     sync.RWMutex
+	models map[string]ModelRef
+}
+
+var _ ModelResolver = (*ModelRegistry)(nil)
+
+// NewModelRegistry creates a registry with the given aliases.
+func NewModelRegistry(models map[string]ModelRef) *ModelRegistry {
+	r := &ModelRegistry{models: make(map[string]ModelRef, len(models))}
+	for alias, ref := range models {
+		r.models[alias] = ref
+	}
+
+	return r
+}
+
+// Resolve implements ModelResolver.
+func (r *ModelRegistry) Resolve(_ context.Context, alias string) (ModelRef, error) {
+	r.mu.RLock()
+	ref, ok := r.models[alias]
+	r.mu.RUnlock()
+	if !ok {
+		return ModelRef{}, fmt.Errorf("%w: %q", ErrUnknownModelAlias, alias)
+	}
+
+	return ref, nil
+}
+
+// Set registers the alias, replacing its previous model version if any.
+func (r *ModelRegistry) Set(alias string, ref ModelRef) {
+	r.mu.Lock()
+	r.models[alias] = ref
+	r.mu.Unlock()
+}
+
+// Replace replaces all the aliases of the registry, e.g. when the configuration is reloaded.
+func (r *ModelRegistry) Replace(models map[string]ModelRef) {
+	replaced := make(map[string]ModelRef, len(models))
+	for alias, ref := range models {
+		replaced[alias] = ref
+	}
+
+	r.mu.Lock()
+	r.models = replaced
+	r.mu.Unlock()
+}
+
+// Aliases returns a copy of the aliases of the registry.
+func (r *ModelRegistry) Aliases() map[string]ModelRef {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	models := make(map[string]ModelRef, len(r.models))
+	for alias, ref := range r.models {
+		models[alias] = ref
+	}
+
+	return models
+}
@@ -6,6 +6,10 @@ import (
 	"github.com/clinia/models-client-go/cliniamodel/datatype"
 )
 
+// OutputPadValue pads the BYTES outputs whose elements vary in number across the batch (e.g. the chunks of
+// each text), so that they fit a rectangular shape.
+const OutputPadValue = "pad"
+
 type Output struct {
 	Name     string
 	Shape    []int64
@@ -31,6 +35,55 @@ func (o *Output) StringMatrixContent() ([][]string, error) {
 	return reshapeArray(o.Content.StringContents, o.Shape)
 }
 
+// Fp32TensorContent reshape the content to a 3D tensor of float32 given the shape of the output.
+func (o *Output) Fp32TensorContent() ([][][]float32, error) {
+	if o.Datatype != datatype.Fp32 {
+		return nil, errors.New("datatype is not float32")
+	}
+	if len(o.Shape) != 3 {
+		return nil, errors.New("shape must have exactly three dimensions")
+	}
+
+	matrix, err := reshapeArray(o.Content.Fp32Contents, []int64{o.Shape[0] * o.Shape[1], o.Shape[2]})
+	if err != nil {
+		return nil, err
+	}
+
+	return reshapeArray(matrix, o.Shape[:2])
+}
+
+// Int32VectorContent returns the content as one int32 per batch element, given the output is of shape
+// [batch] or [batch, 1].
+func (o *Output) Int32VectorContent() ([]int32, error) {
+	if o.Datatype != datatype.Int32 {
+		return nil, errors.New("datatype is not int32")
+	}
+
+	return vectorArray(o.Content.Int32Contents, o.Shape)
+}
+
+// BoolVectorContent returns the content as one bool per batch element, given the output is of shape
+// [batch] or [batch, 1].
+func (o *Output) BoolVectorContent() ([]bool, error) {
+	if o.Datatype != datatype.Bool {
+		return nil, errors.New("datatype is not bool")
+	}
+
+	return vectorArray(o.Content.BoolContents, o.Shape)
+}
+
+// vectorArray checks that the content has one element per batch element given the shape of the output.
+func vectorArray[T any](array []T, shape []int64) ([]T, error) {
+	if len(shape) == 0 || len(shape) > 2 || (len(shape) == 2 && shape[1] != 1) {
+		return nil, errors.New("shape must be [batch] or [batch, 1]")
+	}
+	if int64(len(array)) != shape[0] {
+		return nil, errors.New("the total number of elements does not match the specified dimensions")
+	}
+
+	return array, nil
+}
+
 // reshapeArray reshapes the content to a 2D matrix given the shape of the output.
 func reshapeArray[T any](array []T, shape []int64) ([][]T, error) {
 	if len(shape) != 2 {
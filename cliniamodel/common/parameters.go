@@ -0,0 +1,95 @@
+package common
+
+// Well-known inference request parameters understood by the Triton server.
+const (
+	// ParameterPriority is the priority of the request, 1 being the highest priority. Triton only accepts an
+	// int64 priority.
+	ParameterPriority = "priority"
+	// ParameterTimeout is the timeout of the request, in microseconds. Triton only accepts an int64 timeout.
+	ParameterTimeout = "timeout"
+	// ParameterSequenceID is the identifier of the sequence the request belongs to. Triton only accepts int64
+	// and string IDs.
+	ParameterSequenceID = "sequence_id"
+	// ParameterSequenceStart marks the first request of a sequence.
+	ParameterSequenceStart = "sequence_start"
+	// ParameterSequenceEnd marks the last request of a sequence.
+	ParameterSequenceEnd = "sequence_end"
+	// ParameterFinalResponse marks the last response of a request to a decoupled model, which can return
+	// any number of responses per request.
+	ParameterFinalResponse = "triton_final_response"
+)
+
+// Parameters are the parameters of an inference request or response. Besides the well-known
+// parameters, models can accept arbitrary parameters (e.g. a truncation mode or normalization flag).
+// The supported value types are bool, int, int64, uint64, float64 and string. The setters allocate
+// nil parameters, so that the parameters of a zero request can be set directly.
+type Parameters map[string]any
+
+// SetBool sets a boolean parameter.
+func (p *Parameters) SetBool(key string, value bool) {
+	p.set(key, value)
+}
+
+// SetInt64 sets an integer parameter.
+func (p *Parameters) SetInt64(key string, value int64) {
+	p.set(key, value)
+}
+
+// SetUint64 sets an unsigned integer parameter.
+func (p *Parameters) SetUint64(key string, value uint64) {
+	p.set(key, value)
+}
+
+// SetFloat64 sets a floating point parameter.
+func (p *Parameters) SetFloat64(key string, value float64) {
+	p.set(key, value)
+}
+
+// SetString sets a string parameter.
+func (p *Parameters) SetString(key string, value string) {
+	p.set(key, value)
+}
+
+// set sets a parameter, allocating the parameters if nil.
+func (p *Parameters) set(key string, value any) {
+	if *p == nil {
+		*p = make(Parameters)
+	}
+	(*p)[key] = value
+}
+
+// GetBool returns the boolean parameter with the given key, if any.
+func (p Parameters) GetBool(key string) (bool, bool) {
+	value, ok := p[key].(bool)
+	return value, ok
+}
+
+// GetInt64 returns the integer parameter with the given key, if any.
+func (p Parameters) GetInt64(key string) (int64, bool) {
+	switch value := p[key].(type) {
+	case int64:
+		return value, true
+	case int:
+		return int64(value), true
+	default:
+		return 0, false
+	}
+}
+
+// GetUint64 returns the unsigned integer parameter with the given key, if any.
+func (p Parameters) GetUint64(key string) (uint64, bool) {
+	value, ok := p[key].(uint64)
+	return value, ok
+}
+
+// GetFloat64 returns the floating point parameter with the given key, if any.
+func (p Parameters) GetFloat64(key string) (float64, bool) {
+	value, ok := p[key].(float64)
+	return value, ok
+}
+
+// GetString returns the string parameter with the given key, if any.
+func (p Parameters) GetString(key string) (string, bool) {
+	value, ok := p[key].(string)
+	return value, ok
+}
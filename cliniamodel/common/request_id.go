@@ -0,0 +1,65 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MetadataRequestID is the gRPC metadata key carrying the request ID to the model server.
+const MetadataRequestID = "x-request-id"
+
+// RequestIDGenerator generates the IDs of the requests made without one.
+type RequestIDGenerator func() string
+
+// UUIDRequestIDGenerator generates random UUIDv4 request IDs.
+func UUIDRequestIDGenerator() string {
+	return uuid.NewString()
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID. Requests made with the
+// returned context without an explicit ID use this one (e.g. the ID of an incoming HTTP request).
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestID resolves the ID of a request: the explicit ID if not empty, then the ID carried by ctx,
+// then a new ID from the generator. UUIDRequestIDGenerator is used if generator is nil.
+func RequestID(ctx context.Context, id string, generator RequestIDGenerator) string {
+	if id != "" {
+		return id
+	}
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return id
+	}
+	if generator == nil {
+		generator = UUIDRequestIDGenerator
+	}
+
+	return generator()
+}
+
+// RequestError wraps the error of a request with the ID of the request.
+type RequestError struct {
+	// RequestID is the ID of the failed request.
+	RequestID string
+	// Err is the error of the request.
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("request %s: %v", e.RequestID, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
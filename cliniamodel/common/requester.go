@@ -2,27 +2,74 @@ package common
 
 import (
 	"context"
+	"crypto/tls"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Requester interface {
 	// Infer sends a request to the model server to perform inference on the given inputs.
 	Infer(ctx context.Context, req InferRequest) (*InferResponse, error)
-	// Stream sends a request to the model server to perform inference on the given inputs and returns a channel to stream the output.
-	Stream(ctx context.Context, modelName, modelVersion string, inputs []Input) (chan<- string, error)
+	// Stream opens a bidirectional inference stream with the model server. Responses are matched with
+	// their request by ID, so the requests pending on a stream must have distinct IDs.
+	Stream(ctx context.Context) (InferStream, error)
 	// Ready checks if the model is ready to receive requests.
 	Ready(ctx context.Context, modelName, modelVersion string) error
 	// Health checks if the server is ready to receive requests.
 	Health(ctx context.Context) error
+	// Metadata returns the metadata of the given model version.
+	Metadata(ctx context.Context, modelName, modelVersion string) (*ModelMetadata, error)
+	// RepositoryIndex returns the models available in the model repository of the server.
+	RepositoryIndex(ctx context.Context) ([]ModelIndex, error)
+	// Statistics returns the cumulative inference statistics of the given model version.
+	// If modelName is empty, the statistics of the given version of all models are returned. If modelVersion
+	// is empty, the statistics of all versions of the model are returned.
+	Statistics(ctx context.Context, modelName, modelVersion string) ([]ModelStatistics, error)
 	// Close closes the connection to the model server.
 	Close() error
 }
 
+// DefaultMaxElementBytes is the default maximum size in bytes of a single element of a BYTES output tensor.
+const DefaultMaxElementBytes = 64 << 20
+
 type RequesterConfig struct {
 	Host Host
+	// TLSConfig is the TLS configuration used for HTTPS hosts. The system defaults are used if nil.
+	TLSConfig *tls.Config
+	// MaxRequestBytes is the budget in bytes of the serialized inputs of a single request. Larger inference
+	// requests are split along the batch dimension and their outputs merged back. Since the budget does not
+	// account for the request envelope, it should leave some headroom below the gRPC message size limit.
+	// Requests are never split if 0.
+	MaxRequestBytes int
+	// MaxElementBytes is the maximum size in bytes of a single element of a BYTES output tensor. Responses
+	// with larger elements are rejected with a TensorDecodeError. Defaults to DefaultMaxElementBytes.
+	MaxElementBytes int
+	// ModelNaming maps the model names and versions to the ones known by the model server.
+	// Defaults to ComposedModelNaming.
+	ModelNaming ModelNaming
+	// TracerProvider is used to create the spans of the requests. Defaults to the global tracer provider.
+	TracerProvider trace.TracerProvider
+	// Propagator is used to propagate the trace context to the model server. Defaults to W3C trace-context.
+	Propagator propagation.TextMapPropagator
+	// Metrics records the metrics of the requests. No metrics are recorded if nil.
+	Metrics MetricsRecorder
+	// Logger receives the structured events of the requester. Nothing is logged if nil.
+	Logger *slog.Logger
+	// SlowRequestThreshold is the duration above which a request is logged as slow. Disabled if 0.
+	SlowRequestThreshold time.Duration
+	// LogInputs enables logging the input texts of slow requests and decoding failures. Since the input
+	// texts can contain sensitive data, they are passed through RedactInput, when set, before being logged.
+	LogInputs bool
+	// RedactInput redacts an input text before it is logged.
+	RedactInput func(text string) string
 }
 
 type InferRequest struct {
-	// ID is a unique identifier for the request.
+	// ID is a unique identifier for the request. It is sent to the model server in the x-request-id metadata.
+	// If empty, the ID carried by the context is used, if any.
 	ID string
 	// ModelName is the name of the model to be used for inference.
 	ModelName string
@@ -32,11 +79,28 @@ type InferRequest struct {
 	Inputs []Input
 	// OutputKeys will be a list of keys to be used to access the outputs.
 	OutputKeys []string
+	// Parameters are the optional parameters of the request (e.g. priority, model-specific options).
+	Parameters Parameters
 }
 
 type InferResponse struct {
 	// ID is a unique identifier for the response.
 	ID string
-	// Outputs will be a list of outputs for the given inputs.
+	// Outputs will be a list of outputs for the given inputs. The outputs are not guaranteed to be
+	// in the order of the requested output keys, use Output to look them up by name.
 	Outputs []Output
+	// Parameters are the parameters returned by the model server, if any.
+	Parameters Parameters
+}
+
+// Output returns the output with the given name. An *OutputMismatchError is returned if the response
+// does not contain the output.
+func (r *InferResponse) Output(name string) (*Output, error) {
+	for i := range r.Outputs {
+		if r.Outputs[i].Name == name {
+			return &r.Outputs[i], nil
+		}
+	}
+
+	return nil, &OutputMismatchError{Missing: []string{name}}
 }
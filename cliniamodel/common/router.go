@@ -0,0 +1,201 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+)
+
+// RouteTotalWeight is the sum of the weights of the versions of a route: the weights are percentages.
+const RouteTotalWeight = 100
+
+// WeightedModel is a model version receiving a share of the traffic of a routed alias.
+type WeightedModel struct {
+	ModelRef `yaml:",inline"`
+	// Weight is the percentage of the traffic of the alias sent to the model version. The weights of the
+	// versions of an alias must sum to RouteTotalWeight.
+	Weight int `json:"weight" yaml:"weight"`
+}
+
+type routingKey struct{}
+
+// ContextWithRoutingKey returns a copy of ctx carrying the given routing key. Requests made with the same
+// routing key (e.g. a user or tenant ID) are routed to the same model version, as long as the weights of
+// the route do not change.
+func ContextWithRoutingKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, routingKey{}, key)
+}
+
+// RoutingKeyFromContext returns the routing key carried by ctx, if any.
+func RoutingKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(routingKey{}).(string)
+	return key, ok && key != ""
+}
+
+// RouterConfig configures a Router.
+type RouterConfig struct {
+	// Routes maps the routed aliases to the model versions sharing their traffic.
+	Routes map[string][]WeightedModel
+	// Fallback resolves the aliases without a route. Unknown aliases are rejected if nil.
+	Fallback ModelResolver
+	// Metrics records the model version each request is routed to. Defaults to NoopMetricsRecorder.
+	Metrics MetricsRecorder
+}
+
+// Router is a ModelResolver splitting the traffic of model aliases between model versions according to
+// their weights, e.g. to send a percentage of the traffic to a new version. Requests carrying a routing key
+// (see ContextWithRoutingKey) are assigned to a version by hashing the key, others are assigned randomly.
+//
+// The versions of a route are assigned consecutive ranges of a fixed hash space of RouteTotalWeight points, in
+// order. Since the weights always sum to RouteTotalWeight, a caller only moves to another version when the range
+// of its version shrinks: listing the candidate version last and ramping up its weight at the expense of the
+// version listed before it only moves callers from that version to the candidate. Taking the weight from
+// other versions also shifts the ranges in between, which moves callers between those versions.
+// Router is safe for concurrent use.
+type Router struct {
+	fallback ModelResolver
+	metrics  MetricsRecorder
+
+	mu     sync.RWMutex
+	routes map[string]route
+}
+
+var (
+	_ ModelResolver    = (*Router)(nil)
+	_ VersionsResolver = (*Router)(nil)
+)
+
+// route holds the model versions of a routed alias with a positive weight.
+type route struct {
+	models []WeightedModel
+}
+
+// NewRouter creates a router with the given routes.
+func NewRouter(cfg RouterConfig) (*Router, error) {
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = NoopMetricsRecorder{}
+	}
+
+	r := &Router{
+		fallback: cfg.Fallback,
+		metrics:  metrics,
+		routes:   make(map[string]route, len(cfg.Routes)),
+	}
+	for alias, models := range cfg.Routes {
+		if err := r.SetRoute(alias, models); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// SetRoute sets the model versions of the alias, replacing its previous route if any.
+func (r *Router) SetRoute(alias string, models []WeightedModel) error {
+	rt, err := newRoute(models)
+	if err != nil {
+		return fmt.Errorf("invalid route %q: %w", alias, err)
+	}
+
+	r.mu.Lock()
+	r.routes[alias] = rt
+	r.mu.Unlock()
+
+	return nil
+}
+
+// RemoveRoute removes the route of the alias, which is then resolved by the fallback resolver.
+func (r *Router) RemoveRoute(alias string) {
+	r.mu.Lock()
+	delete(r.routes, alias)
+	r.mu.Unlock()
+}
+
+// Resolve implements ModelResolver.
+func (r *Router) Resolve(ctx context.Context, alias string) (ModelRef, error) {
+	r.mu.RLock()
+	rt, ok := r.routes[alias]
+	r.mu.RUnlock()
+	if !ok {
+		if r.fallback == nil {
+			return ModelRef{}, fmt.Errorf("%w: %q", ErrUnknownModelAlias, alias)
+		}
+		return r.fallback.Resolve(ctx, alias)
+	}
+
+	var point uint64
+	if key, ok := RoutingKeyFromContext(ctx); ok {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(alias))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(key))
+		point = h.Sum64() % RouteTotalWeight
+	} else {
+		point = rand.Uint64N(RouteTotalWeight)
+	}
+
+	ref := rt.pick(point)
+	r.metrics.ObserveRoute(alias, ref.Name, ref.Version)
+
+	return ref, nil
+}
+
+// ResolveVersions implements VersionsResolver. It returns the versions of the route of the alias with a positive
+// weight, without recording a routing decision.
+func (r *Router) ResolveVersions(ctx context.Context, alias string) ([]ModelRef, error) {
+	r.mu.RLock()
+	rt, ok := r.routes[alias]
+	r.mu.RUnlock()
+	if !ok {
+		if r.fallback == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownModelAlias, alias)
+		}
+		return ResolveVersions(ctx, r.fallback, alias)
+	}
+
+	refs := make([]ModelRef, len(rt.models))
+	for i, model := range rt.models {
+		refs[i] = model.ModelRef
+	}
+
+	return refs, nil
+}
+
+func newRoute(models []WeightedModel) (route, error) {
+	rt := route{models: make([]WeightedModel, 0, len(models))}
+	total := 0
+	for _, model := range models {
+		if model.Name == "" || model.Version == "" {
+			return route{}, errors.New("name and version are required")
+		}
+		if model.Weight < 0 {
+			return route{}, fmt.Errorf("negative weight for %s", model.ModelRef)
+		}
+		total += model.Weight
+		if model.Weight > 0 {
+			rt.models = append(rt.models, model)
+		}
+	}
+	if total != RouteTotalWeight {
+		return route{}, fmt.Errorf("weights sum to %d instead of %d", total, RouteTotalWeight)
+	}
+
+	return rt, nil
+}
+
+// pick returns the model version whose range of the hash space contains the point.
+func (rt route) pick(point uint64) ModelRef {
+	for _, model := range rt.models {
+		weight := uint64(model.Weight)
+		if point < weight {
+			return model.ModelRef
+		}
+		point -= weight
+	}
+
+	return rt.models[len(rt.models)-1].ModelRef
+}
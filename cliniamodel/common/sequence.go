@@ -0,0 +1,105 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sync"
+)
+
+// ErrSequenceEnded is returned when a request is made on a sequence whose last request was already sent.
+var ErrSequenceEnded = errors.New("sequence has ended")
+
+// Sequence is a handle on an inference sequence of a stateful model served with the Triton sequence batcher.
+// It allocates the sequence ID, marks the first and last requests of the sequence and guarantees that the
+// requests of the sequence are sent one after the other, in the order of the calls.
+type Sequence struct {
+	requester Requester
+	id        int64
+
+	mu      sync.Mutex
+	started bool
+	ended   bool
+}
+
+// NewSequence creates a new sequence on the given requester with a randomly allocated ID.
+func NewSequence(requester Requester) *Sequence {
+	// Triton reserves the ID 0 for requests that are not part of a sequence, and only accepts signed
+	// integer (or string) IDs.
+	return &Sequence{
+		requester: requester,
+		id:        rand.Int64N(math.MaxInt64) + 1,
+	}
+}
+
+// ID returns the identifier of the sequence.
+func (s *Sequence) ID() int64 {
+	return s.id
+}
+
+// Infer sends the next request of the sequence. The first request of the sequence is marked as its start.
+func (s *Sequence) Infer(ctx context.Context, req InferRequest) (*InferResponse, error) {
+	var res *InferResponse
+	err := s.do(req, false, func(req InferRequest) error {
+		var err error
+		res, err = s.requester.Infer(ctx, req)
+		return err
+	})
+
+	return res, err
+}
+
+// InferLast sends the last request of the sequence. No request can be made on the sequence afterwards.
+func (s *Sequence) InferLast(ctx context.Context, req InferRequest) (*InferResponse, error) {
+	var res *InferResponse
+	err := s.do(req, true, func(req InferRequest) error {
+		var err error
+		res, err = s.requester.Infer(ctx, req)
+		return err
+	})
+
+	return res, err
+}
+
+// Send sends the next request of the sequence on the given stream. The first request of the sequence
+// is marked as its start.
+func (s *Sequence) Send(stream InferStream, req InferRequest) error {
+	return s.do(req, false, stream.Send)
+}
+
+// SendLast sends the last request of the sequence on the given stream. No request can be made on the
+// sequence afterwards.
+func (s *Sequence) SendLast(stream InferStream, req InferRequest) error {
+	return s.do(req, true, stream.Send)
+}
+
+// do sends the request with the sequence parameters. The sequence lock is held while sending so that
+// the requests of the sequence are never sent concurrently.
+func (s *Sequence) do(req InferRequest, last bool, send func(req InferRequest) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return ErrSequenceEnded
+	}
+
+	// Copy the parameters to avoid mutating the caller's request.
+	params := make(Parameters, len(req.Parameters)+3)
+	for key, value := range req.Parameters {
+		params[key] = value
+	}
+	params.SetInt64(ParameterSequenceID, s.id)
+	params.SetBool(ParameterSequenceStart, !s.started)
+	params.SetBool(ParameterSequenceEnd, last)
+	req.Parameters = params
+
+	if err := send(req); err != nil {
+		return err
+	}
+
+	s.started = true
+	s.ended = last
+
+	return nil
+}
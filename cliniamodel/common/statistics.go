@@ -0,0 +1,161 @@
+package common
+
+import (
+	"time"
+)
+
+// StatisticDuration is a cumulative counter paired with the total time spent for a statistic.
+type StatisticDuration struct {
+	// Count is the cumulative number of times the statistic occurred.
+	Count uint64
+	// Total is the cumulative duration of the statistic.
+	Total time.Duration
+}
+
+// Average returns the average duration per occurrence, or 0 if the statistic never occurred.
+func (d StatisticDuration) Average() time.Duration {
+	if d.Count == 0 {
+		return 0
+	}
+
+	return d.Total / time.Duration(d.Count)
+}
+
+// Sub returns the difference between d and a previous sample of the same statistic.
+// If the counter went backwards (e.g. the server restarted), d is returned as is.
+func (d StatisticDuration) Sub(prev StatisticDuration) StatisticDuration {
+	if d.Count < prev.Count || d.Total < prev.Total {
+		return d
+	}
+
+	return StatisticDuration{
+		Count: d.Count - prev.Count,
+		Total: d.Total - prev.Total,
+	}
+}
+
+// InferStatistics holds the aggregate inference statistics of a model version.
+type InferStatistics struct {
+	// Success is the count and time of successful inference requests, including cache hits.
+	Success StatisticDuration
+	// Fail is the count and time of failed inference requests.
+	Fail StatisticDuration
+	// Queue is the count and time spent by requests waiting in the scheduling queue.
+	Queue StatisticDuration
+	// ComputeInput is the count and time spent preparing the input tensors.
+	ComputeInput StatisticDuration
+	// ComputeInfer is the count and time spent executing the model.
+	ComputeInfer StatisticDuration
+	// ComputeOutput is the count and time spent extracting the output tensors.
+	ComputeOutput StatisticDuration
+	// CacheHit is the count and time spent on response cache hits.
+	CacheHit StatisticDuration
+	// CacheMiss is the count and time spent on response cache misses.
+	CacheMiss StatisticDuration
+}
+
+// BatchStatistics holds the compute statistics of a model version for a given batch size.
+type BatchStatistics struct {
+	// BatchSize is the size of the batch executed by the model.
+	BatchSize uint64
+	// ComputeInput is the count and time spent preparing the input tensors.
+	ComputeInput StatisticDuration
+	// ComputeInfer is the count and time spent executing the model.
+	ComputeInfer StatisticDuration
+	// ComputeOutput is the count and time spent extracting the output tensors.
+	ComputeOutput StatisticDuration
+}
+
+// ModelStatistics holds the cumulative statistics reported by the model server for a model version.
+type ModelStatistics struct {
+	// ModelName is the name of the model.
+	ModelName string
+	// ModelVersion is the version of the model.
+	ModelVersion string
+	// LastInference is the time of the last inference request made for the model.
+	LastInference time.Time
+	// InferenceCount is the cumulative count of successful inferences. Each element of a batch counts as an inference.
+	InferenceCount uint64
+	// ExecutionCount is the cumulative count of successful model executions. A batched execution counts once.
+	ExecutionCount uint64
+	// Inference holds the aggregate inference statistics.
+	Inference InferStatistics
+	// Batches holds the compute statistics for each batch size executed by the model.
+	Batches []BatchStatistics
+	// CollectedAt is the time at which the statistics were collected by the client.
+	CollectedAt time.Time
+}
+
+// StatisticsDelta summarizes the activity of a model version between two statistics samples.
+type StatisticsDelta struct {
+	// ModelName is the name of the model.
+	ModelName string
+	// ModelVersion is the version of the model.
+	ModelVersion string
+	// Interval is the time elapsed between the two samples.
+	Interval time.Duration
+	// InferenceCount is the number of successful inferences over the interval.
+	InferenceCount uint64
+	// ExecutionCount is the number of successful model executions over the interval.
+	ExecutionCount uint64
+	// SuccessCount is the number of successful requests over the interval.
+	SuccessCount uint64
+	// FailCount is the number of failed requests over the interval.
+	FailCount uint64
+	// AvgRequest is the average server-side duration of a successful request.
+	AvgRequest time.Duration
+	// AvgQueue is the average time spent by a request in the scheduling queue.
+	AvgQueue time.Duration
+	// AvgComputeInput is the average time spent preparing the input tensors.
+	AvgComputeInput time.Duration
+	// AvgComputeInfer is the average time spent executing the model.
+	AvgComputeInfer time.Duration
+	// AvgComputeOutput is the average time spent extracting the output tensors.
+	AvgComputeOutput time.Duration
+	// Throughput is the number of successful inferences per second over the interval.
+	Throughput float64
+}
+
+// Sub computes the activity between a previous statistics sample and s.
+// Both samples are expected to belong to the same model version.
+func (s ModelStatistics) Sub(prev ModelStatistics) StatisticsDelta {
+	interval := s.CollectedAt.Sub(prev.CollectedAt)
+
+	success := s.Inference.Success.Sub(prev.Inference.Success)
+	fail := s.Inference.Fail.Sub(prev.Inference.Fail)
+	queue := s.Inference.Queue.Sub(prev.Inference.Queue)
+	computeInput := s.Inference.ComputeInput.Sub(prev.Inference.ComputeInput)
+	computeInfer := s.Inference.ComputeInfer.Sub(prev.Inference.ComputeInfer)
+	computeOutput := s.Inference.ComputeOutput.Sub(prev.Inference.ComputeOutput)
+
+	delta := StatisticsDelta{
+		ModelName:        s.ModelName,
+		ModelVersion:     s.ModelVersion,
+		Interval:         interval,
+		InferenceCount:   subCounter(s.InferenceCount, prev.InferenceCount),
+		ExecutionCount:   subCounter(s.ExecutionCount, prev.ExecutionCount),
+		SuccessCount:     success.Count,
+		FailCount:        fail.Count,
+		AvgRequest:       success.Average(),
+		AvgQueue:         queue.Average(),
+		AvgComputeInput:  computeInput.Average(),
+		AvgComputeInfer:  computeInfer.Average(),
+		AvgComputeOutput: computeOutput.Average(),
+	}
+
+	if interval > 0 {
+		delta.Throughput = float64(delta.InferenceCount) / interval.Seconds()
+	}
+
+	return delta
+}
+
+// subCounter returns the difference between two samples of a cumulative counter,
+// treating a counter that went backwards as having been reset.
+func subCounter(current, prev uint64) uint64 {
+	if current < prev {
+		return current
+	}
+
+	return current - prev
+}
@@ -0,0 +1,15 @@
+package common
+
+// InferStream is a bidirectional inference stream with the model server.
+// Send and Recv can be called concurrently from different goroutines, but Send
+// must not be called concurrently with itself, and neither must Recv.
+type InferStream interface {
+	// Send sends an inference request on the stream.
+	Send(req InferRequest) error
+	// Recv receives the next response, matched with its request by ID. The responses of different
+	// requests can be received in any order, and decoupled models can return several responses per
+	// request. It returns io.EOF once the sending side is closed and all the responses have been received.
+	Recv() (*InferResponse, error)
+	// CloseSend closes the sending side of the stream.
+	CloseSend() error
+}
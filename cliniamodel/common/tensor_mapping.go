@@ -0,0 +1,31 @@
+package common
+
+// TensorNames maps the default tensor names of the model clients (e.g. "text", "score") to the tensor names of
+// a model (e.g. "input_text", "logits"). Tensors without an entry keep their default name.
+type TensorNames map[string]string
+
+// TensorMapping configures the names of the input and output tensors of the models of the model clients, so that
+// the clients can drive models whose tensors are named differently.
+type TensorMapping struct {
+	// Default maps the tensor names of every model.
+	Default TensorNames `json:"default" yaml:"default"`
+	// Models maps the tensor names of specific models, by model name. Its entries take precedence over Default.
+	Models map[string]TensorNames `json:"models" yaml:"models"`
+	// Discover resolves the tensors that a model does not declare under their mapped name from the metadata of
+	// the model: each of them is matched with the declared tensor of the same datatype that no other tensor of the
+	// client matches. A tensor matching several declared tensors must be mapped explicitly. The metadata of each
+	// model version is fetched once.
+	Discover bool `json:"discover" yaml:"discover"`
+}
+
+// Name returns the name of the tensor of the given default name for the model.
+func (m TensorMapping) Name(modelName, tensor string) string {
+	if name, ok := m.Models[modelName][tensor]; ok {
+		return name
+	}
+	if name, ok := m.Default[tensor]; ok {
+		return name
+	}
+
+	return tensor
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the spans created by the clients.
+const TracerName = "github.com/clinia/models-client-go/cliniamodel"
+
+// Attributes set on the spans created by the clients.
+const (
+	AttributeModelName    = attribute.Key("cliniamodel.model.name")
+	AttributeModelVersion = attribute.Key("cliniamodel.model.version")
+	AttributeBatchSize    = attribute.Key("cliniamodel.batch_size")
+	AttributePayloadBytes = attribute.Key("cliniamodel.payload_bytes")
+	AttributeOutcome      = attribute.Key("cliniamodel.outcome")
+	AttributeRequestID    = attribute.Key("cliniamodel.request_id")
+)
+
+// Outcomes of an operation, as recorded on spans and metrics.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Tracer returns the tracer of the clients from the given provider.
+// The global tracer provider is used if tp is nil.
+func Tracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return tp.Tracer(TracerName)
+}
+
+// Propagator returns the given propagator, or the W3C trace-context propagator if p is nil.
+func Propagator(p propagation.TextMapPropagator) propagation.TextMapPropagator {
+	if p == nil {
+		return propagation.TraceContext{}
+	}
+
+	return p
+}
+
+// StartModelSpan starts a span for an operation on the given model version.
+func StartModelSpan(ctx context.Context, tracer trace.Tracer, name string, modelName, modelVersion string, batchSize int, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithAttributes(
+		AttributeModelName.String(modelName),
+		AttributeModelVersion.String(modelVersion),
+		AttributeBatchSize.Int(batchSize),
+	))
+
+	return tracer.Start(ctx, name, opts...)
+}
+
+// EndSpan records the outcome of the operation on the span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(AttributeOutcome.String(OutcomeError))
+	} else {
+		span.SetAttributes(AttributeOutcome.String(OutcomeSuccess))
+	}
+
+	span.End()
+}
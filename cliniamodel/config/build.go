@@ -0,0 +1,192 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel"
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/clinia/models-client-go/cliniamodel/requestergrpc"
+	"github.com/clinia/models-client-go/cliniamodel/requesterhttp/filesvcclient"
+)
+
+// NewClient creates a models client from the configuration. The model aliases and routes of the configuration
+// are resolved by the resolver of ModelResolver, unless another resolver is provided in the options, and the
+// tensor names of the models are mapped by the tensor mapping of the configuration. The metrics of the model
+// server and file service requests and the routing decisions are recorded with metrics, unless it is nil.
+func NewClient(ctx context.Context, cfg Config, metrics common.MetricsRecorder, opts ...common.ClientOption) (*cliniamodel.Client, error) {
+	clientCfg, err := cfg.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if metrics != nil {
+		clientCfg.Requester.Metrics = metrics
+		if clientCfg.FileServiceURL != "" {
+			clientCfg.FileServiceOptions = append(clientCfg.FileServiceOptions, filesvcclient.WithMetrics(metrics))
+		}
+	}
+
+	resolver, err := cfg.ModelResolver(metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]common.ClientOption{
+		common.WithModelResolver(resolver),
+		common.WithTensorMapping(cfg.Tensors),
+	}, opts...)
+	return cliniamodel.NewClient(ctx, clientCfg, opts...)
+}
+
+// ModelRegistry returns a registry of the model aliases of the configuration.
+func (c Config) ModelRegistry() *common.ModelRegistry {
+	return common.NewModelRegistry(c.Models)
+}
+
+// ModelResolver returns the resolver of the model aliases of the configuration: a common.Router splitting
+// the traffic of the routed aliases and falling back to the registry of the other aliases if the
+// configuration has routes, the registry otherwise. The routing decisions are recorded with metrics.
+func (c Config) ModelResolver(metrics common.MetricsRecorder) (common.ModelResolver, error) {
+	registry := c.ModelRegistry()
+	if len(c.Routes) == 0 {
+		return registry, nil
+	}
+
+	return common.NewRouter(common.RouterConfig{
+		Routes:   c.Routes,
+		Fallback: registry,
+		Metrics:  metrics,
+	})
+}
+
+// ClientConfig builds the configuration of cliniamodel.NewClient. The certificates of the TLS configurations
+// are read from their files. Other settings, such as the logger or the metrics recorder, can be set on the
+// returned configuration.
+func (c Config) ClientConfig() (cliniamodel.Config, error) {
+	host, err := common.ParseHost(c.ModelServer.Host)
+	if err != nil {
+		return cliniamodel.Config{}, fmt.Errorf("modelServer.host: %w", err)
+	}
+
+	clientCfg := cliniamodel.Config{
+		Requester: common.RequesterConfig{
+			Host:            host,
+			MaxRequestBytes: c.ModelServer.MaxRequestBytes,
+			MaxElementBytes: c.ModelServer.MaxElementBytes,
+		},
+		RequesterOptions: c.ModelServer.requesterOptions(),
+	}
+
+	if host.Secure() {
+		clientCfg.Requester.TLSConfig, err = c.ModelServer.TLS.build()
+		if err != nil {
+			return cliniamodel.Config{}, fmt.Errorf("modelServer.tls: %w", err)
+		}
+	}
+
+	if c.FileService.URL != "" {
+		clientCfg.FileServiceURL = c.FileService.URL
+		clientCfg.FileServiceOptions, err = c.FileService.clientOptions()
+		if err != nil {
+			return cliniamodel.Config{}, err
+		}
+	}
+
+	return clientCfg, nil
+}
+
+func (c ModelServerConfig) requesterOptions() []requestergrpc.Option {
+	var opts []requestergrpc.Option
+	if c.Timeout > 0 {
+		opts = append(opts, requestergrpc.WithTimeout(time.Duration(c.Timeout)))
+	}
+	if c.Retry.MaxAttempts > 1 {
+		opts = append(opts, requestergrpc.WithRetryPolicy(requestergrpc.RetryPolicy{
+			MaxAttempts:    c.Retry.MaxAttempts,
+			InitialBackoff: time.Duration(c.Retry.InitialBackoff),
+			MaxBackoff:     time.Duration(c.Retry.MaxBackoff),
+		}))
+	}
+	if c.MaxMessageBytes > 0 {
+		opts = append(opts, requestergrpc.WithMaxMessageSize(c.MaxMessageBytes, c.MaxMessageBytes))
+	}
+	if c.Gzip {
+		opts = append(opts, requestergrpc.WithGzipCompression())
+	}
+	switch {
+	case c.Auth.APIKey != "":
+		opts = append(opts, requestergrpc.WithAPIKey(c.Auth.APIKey))
+	case c.Auth.BearerToken != "":
+		opts = append(opts, requestergrpc.WithBearer(c.Auth.BearerToken))
+	}
+	if c.Auth.AllowInsecure {
+		opts = append(opts, requestergrpc.WithInsecureAuthorization())
+	}
+
+	return opts
+}
+
+func (c FileServiceConfig) clientOptions() ([]filesvcclient.ClientOption, error) {
+	var opts []filesvcclient.ClientOption
+	if !c.TLS.empty() {
+		tlsConfig, err := c.TLS.build()
+		if err != nil {
+			return nil, fmt.Errorf("fileService.tls: %w", err)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		opts = append(opts, filesvcclient.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+	if c.Timeout > 0 {
+		opts = append(opts, filesvcclient.WithTimeout(time.Duration(c.Timeout)))
+	}
+	opts = append(opts,
+		filesvcclient.WithAPIKey(c.Auth.APIKey),
+		filesvcclient.WithBearer(c.Auth.BearerToken),
+	)
+
+	return opts, nil
+}
+
+// build builds the TLS configuration, or returns nil to use the system defaults if the configuration is empty.
+func (c TLSConfig) build() (*tls.Config, error) {
+	if c.empty() {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: c.ServerName,
+		// #nosec G402 -- opt-in, for testing only.
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificate found in " + c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
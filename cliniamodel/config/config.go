@@ -0,0 +1,249 @@
+// Package config loads the configuration of the Clinia models client from a YAML or JSON file and from
+// environment variables, so that every service configures the client identically.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// Config is the configuration of the models client.
+type Config struct {
+	// ModelServer configures the connection to the model server.
+	ModelServer ModelServerConfig `json:"modelServer" yaml:"modelServer"`
+	// FileService configures the connection to the file service. It is optional.
+	FileService FileServiceConfig `json:"fileService" yaml:"fileService"`
+	// Models maps the logical model aliases (e.g. "query-embedder") to model versions.
+	Models map[string]common.ModelRef `json:"models" yaml:"models"`
+	// Routes maps the model aliases whose traffic is split between model versions to the weighted versions.
+	// An alias with a route takes precedence over the same alias in Models.
+	Routes map[string][]common.WeightedModel `json:"routes" yaml:"routes"`
+	// Tensors maps the tensor names of the models whose tensors are not named like the defaults of the clients.
+	Tensors common.TensorMapping `json:"tensors" yaml:"tensors"`
+}
+
+// ModelServerConfig configures the connection to the model server.
+type ModelServerConfig struct {
+	// Host is the URL of the model server, in a format supported by common.ParseHost (e.g. "grpc://triton:8001").
+	Host string `json:"host" yaml:"host"`
+	// TLS configures the transport security of grpcs:// hosts.
+	TLS TLSConfig `json:"tls" yaml:"tls"`
+	// Auth configures the credentials sent to the model server.
+	Auth AuthConfig `json:"auth" yaml:"auth"`
+	// Timeout bounds the duration of the requests. Requests are not bounded if 0.
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+	// Retry configures the retries of the requests failing with a transient error.
+	Retry RetryConfig `json:"retry" yaml:"retry"`
+	// MaxRequestBytes is the budget of the serialized inputs of a request, above which requests are split.
+	// Requests are never split if 0.
+	MaxRequestBytes int `json:"maxRequestBytes" yaml:"maxRequestBytes"`
+	// MaxElementBytes is the maximum size of a single element of a BYTES output tensor.
+	MaxElementBytes int `json:"maxElementBytes" yaml:"maxElementBytes"`
+	// MaxMessageBytes is the maximum size of the messages sent to and received from the model server.
+	MaxMessageBytes int `json:"maxMessageBytes" yaml:"maxMessageBytes"`
+	// Gzip compresses the requests sent to the model server.
+	Gzip bool `json:"gzip" yaml:"gzip"`
+}
+
+// FileServiceConfig configures the connection to the file service.
+type FileServiceConfig struct {
+	// URL is the base URL of the file service (e.g. "http://filesvc:8080"). The client has no file processor if empty.
+	URL string `json:"url" yaml:"url"`
+	// TLS configures the transport security of https:// URLs.
+	TLS TLSConfig `json:"tls" yaml:"tls"`
+	// Auth configures the credentials sent to the file service.
+	Auth AuthConfig `json:"auth" yaml:"auth"`
+	// Timeout bounds the duration of the requests, including the reading of their response. Requests are not
+	// bounded if 0.
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+}
+
+// TLSConfig configures the transport security of a connection. The system defaults are used if empty.
+type TLSConfig struct {
+	// CAFile is the path of the PEM encoded certificate authorities used to verify the server.
+	CAFile string `json:"caFile" yaml:"caFile"`
+	// CertFile and KeyFile are the paths of the PEM encoded client certificate and key, for mutual TLS.
+	CertFile string `json:"certFile" yaml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile"`
+	// ServerName overrides the name used to verify the certificate of the server.
+	ServerName string `json:"serverName" yaml:"serverName"`
+	// InsecureSkipVerify disables the verification of the certificate of the server. For testing only.
+	InsecureSkipVerify bool `json:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+}
+
+// AuthConfig configures the credentials sent with the requests. At most one of them can be set.
+type AuthConfig struct {
+	// APIKey is sent as `Authorization: Api-Key <key>`.
+	APIKey string `json:"apiKey" yaml:"apiKey"`
+	// BearerToken is sent as `Authorization: Bearer <token>`.
+	BearerToken string `json:"bearerToken" yaml:"bearerToken"`
+	// AllowInsecure allows the credentials to be sent in plaintext to a host reached without transport
+	// security. For development only.
+	AllowInsecure bool `json:"allowInsecure" yaml:"allowInsecure"`
+}
+
+// RetryConfig configures the retries of the requests failing with a transient error.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts of a request, including the first one. Requests are not
+	// retried if 1. gRPC caps it to 5.
+	MaxAttempts int `json:"maxAttempts" yaml:"maxAttempts"`
+	// InitialBackoff is the maximum delay before the first retry.
+	InitialBackoff Duration `json:"initialBackoff" yaml:"initialBackoff"`
+	// MaxBackoff is the upper bound of the delay between two attempts.
+	MaxBackoff Duration `json:"maxBackoff" yaml:"maxBackoff"`
+}
+
+// Default returns the default configuration. The host of the model server must still be set.
+func Default() Config {
+	return Config{
+		ModelServer: ModelServerConfig{
+			Timeout: Duration(30 * time.Second),
+			Retry: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: Duration(100 * time.Millisecond),
+				MaxBackoff:     Duration(2 * time.Second),
+			},
+			MaxElementBytes: common.DefaultMaxElementBytes,
+			MaxMessageBytes: 64 << 20,
+		},
+		FileService: FileServiceConfig{
+			Timeout: Duration(2 * time.Minute),
+		},
+	}
+}
+
+// Validate checks that the configuration is complete and consistent.
+func (c Config) Validate() error {
+	var errs []error
+
+	host, err := common.ParseHost(c.ModelServer.Host)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("modelServer.host: %w", err))
+	}
+	if err == nil && !host.Secure() && !c.ModelServer.TLS.empty() {
+		errs = append(errs, errors.New("modelServer.tls: the host is reached without transport security"))
+	}
+	errs = append(errs, c.ModelServer.TLS.validate("modelServer.tls")...)
+	errs = append(errs, c.ModelServer.Auth.validate("modelServer.auth", err == nil && host.Secure())...)
+	if c.ModelServer.Timeout < 0 {
+		errs = append(errs, errors.New("modelServer.timeout: must not be negative"))
+	}
+	errs = append(errs, c.ModelServer.Retry.validate("modelServer.retry")...)
+	if c.ModelServer.MaxRequestBytes < 0 {
+		errs = append(errs, errors.New("modelServer.maxRequestBytes: must not be negative"))
+	}
+	if c.ModelServer.MaxElementBytes < 0 {
+		errs = append(errs, errors.New("modelServer.maxElementBytes: must not be negative"))
+	}
+	if c.ModelServer.MaxMessageBytes < 0 {
+		errs = append(errs, errors.New("modelServer.maxMessageBytes: must not be negative"))
+	}
+	if c.ModelServer.MaxMessageBytes > 0 && c.ModelServer.MaxRequestBytes > c.ModelServer.MaxMessageBytes {
+		errs = append(errs, errors.New("modelServer.maxRequestBytes: must not exceed modelServer.maxMessageBytes"))
+	}
+
+	fileServiceSecure := false
+	if c.FileService.URL != "" {
+		host, err := common.ParseHost(c.FileService.URL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fileService.url: %w", err))
+		}
+		if err == nil && !host.Secure() && !c.FileService.TLS.empty() {
+			errs = append(errs, errors.New("fileService.tls: the URL is reached without transport security"))
+		}
+		fileServiceSecure = err == nil && host.Secure()
+	}
+	errs = append(errs, c.FileService.TLS.validate("fileService.tls")...)
+	errs = append(errs, c.FileService.Auth.validate("fileService.auth", fileServiceSecure)...)
+	if c.FileService.Timeout < 0 {
+		errs = append(errs, errors.New("fileService.timeout: must not be negative"))
+	}
+
+	for alias, ref := range c.Models {
+		if alias == "" {
+			errs = append(errs, errors.New("models: aliases must not be empty"))
+		}
+		if ref.Name == "" || ref.Version == "" {
+			errs = append(errs, fmt.Errorf("models.%s: name and version are required", alias))
+		}
+	}
+
+	for alias, models := range c.Routes {
+		total := 0
+		for _, model := range models {
+			if model.Name == "" || model.Version == "" {
+				errs = append(errs, fmt.Errorf("routes.%s: name and version are required", alias))
+			}
+			if model.Weight < 0 {
+				errs = append(errs, fmt.Errorf("routes.%s: weights must not be negative", alias))
+			}
+			total += model.Weight
+		}
+		if total != common.RouteTotalWeight {
+			errs = append(errs, fmt.Errorf("routes.%s: weights must sum to %d, got %d", alias, common.RouteTotalWeight, total))
+		}
+	}
+
+	errs = append(errs, validateTensorNames("tensors.default", c.Tensors.Default)...)
+	for model, names := range c.Tensors.Models {
+		errs = append(errs, validateTensorNames("tensors.models."+model, names)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateTensorNames(path string, names common.TensorNames) []error {
+	var errs []error
+	for tensor, name := range names {
+		if name == "" {
+			errs = append(errs, fmt.Errorf("%s.%s: must not be empty", path, tensor))
+		}
+	}
+
+	return errs
+}
+
+func (c TLSConfig) empty() bool {
+	return c == TLSConfig{}
+}
+
+func (c TLSConfig) validate(path string) []error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return []error{fmt.Errorf("%s: certFile and keyFile must be set together", path)}
+	}
+
+	return nil
+}
+
+// validate checks the credentials sent to a host, reached with TLS if secure.
+func (c AuthConfig) validate(path string, secure bool) []error {
+	var errs []error
+	if c.APIKey != "" && c.BearerToken != "" {
+		errs = append(errs, fmt.Errorf("%s: apiKey and bearerToken are mutually exclusive", path))
+	}
+	if (c.APIKey != "" || c.BearerToken != "") && !secure && !c.AllowInsecure {
+		errs = append(errs, fmt.Errorf("%s: the credentials would be sent without transport security, set allowInsecure to allow it", path))
+	}
+
+	return errs
+}
+
+func (c RetryConfig) validate(path string) []error {
+	var errs []error
+	if c.MaxAttempts < 0 {
+		errs = append(errs, fmt.Errorf("%s.maxAttempts: must not be negative", path))
+	}
+	if c.MaxAttempts > 1 {
+		if c.InitialBackoff <= 0 {
+			errs = append(errs, fmt.Errorf("%s.initialBackoff: must be positive", path))
+		}
+		if c.MaxBackoff < c.InitialBackoff {
+			errs = append(errs, fmt.Errorf("%s.maxBackoff: must not be less than initialBackoff", path))
+		}
+	}
+
+	return errs
+}
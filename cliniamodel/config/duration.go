@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+)
+
+// Duration is a time.Duration written in the format of time.ParseDuration (e.g. "1m30s") in configuration files.
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// String returns the duration in the format of time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables read by Load.
+const EnvPrefix = "CLINIA_"
+
+// Load loads the configuration from the defaults, then from the file at the given path if not empty, then from
+// the environment variables, and validates it. The file is decoded as JSON if its extension is ".json", as YAML
+// otherwise. Unknown fields are rejected.
+//
+// The environment variables are named after the fields of the configuration, with the EnvPrefix
+// (e.g. CLINIA_MODEL_SERVER_HOST, CLINIA_MODEL_SERVER_RETRY_MAX_ATTEMPTS, CLINIA_FILE_SERVICE_URL). The model
+// aliases are read from CLINIA_MODELS as a comma-separated list of alias=name:version pairs.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := cfg.loadEnv(os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// loadFile decodes the file at the given path on top of the configuration.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read configuration: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(c)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(c)
+		if errors.Is(err, io.EOF) {
+			// The file is empty.
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("decode configuration %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// loadEnv sets the fields of the configuration from the environment variables that are set.
+func (c *Config) loadEnv(lookup func(key string) (string, bool)) error {
+	var errs []error
+	for _, binding := range c.envBindings() {
+		value, ok := lookup(EnvPrefix + binding.name)
+		if !ok {
+			continue
+		}
+		if err := binding.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: %w", EnvPrefix, binding.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// envBinding binds an environment variable to a field of the configuration.
+type envBinding struct {
+	name string
+	set  func(value string) error
+}
+
+func (c *Config) envBindings() []envBinding {
+	bindings := []envBinding{
+		{"MODEL_SERVER_HOST", stringVar(&c.ModelServer.Host)},
+		{"MODEL_SERVER_TIMEOUT", durationVar(&c.ModelServer.Timeout)},
+		{"MODEL_SERVER_RETRY_MAX_ATTEMPTS", intVar(&c.ModelServer.Retry.MaxAttempts)},
+		{"MODEL_SERVER_RETRY_INITIAL_BACKOFF", durationVar(&c.ModelServer.Retry.InitialBackoff)},
+		{"MODEL_SERVER_RETRY_MAX_BACKOFF", durationVar(&c.ModelServer.Retry.MaxBackoff)},
+		{"MODEL_SERVER_MAX_REQUEST_BYTES", intVar(&c.ModelServer.MaxRequestBytes)},
+		{"MODEL_SERVER_MAX_ELEMENT_BYTES", intVar(&c.ModelServer.MaxElementBytes)},
+		{"MODEL_SERVER_MAX_MESSAGE_BYTES", intVar(&c.ModelServer.MaxMessageBytes)},
+		{"MODEL_SERVER_GZIP", boolVar(&c.ModelServer.Gzip)},
+		{"FILE_SERVICE_URL", stringVar(&c.FileService.URL)},
+		{"FILE_SERVICE_TIMEOUT", durationVar(&c.FileService.Timeout)},
+		{"MODELS", c.setModels},
+	}
+	bindings = append(bindings, c.ModelServer.TLS.envBindings("MODEL_SERVER_TLS_")...)
+	bindings = append(bindings, c.ModelServer.Auth.envBindings("MODEL_SERVER_")...)
+	bindings = append(bindings, c.FileService.TLS.envBindings("FILE_SERVICE_TLS_")...)
+	bindings = append(bindings, c.FileService.Auth.envBindings("FILE_SERVICE_")...)
+
+	return bindings
+}
+
+func (c *TLSConfig) envBindings(prefix string) []envBinding {
+	return []envBinding{
+		{prefix + "CA_FILE", stringVar(&c.CAFile)},
+		{prefix + "CERT_FILE", stringVar(&c.CertFile)},
+		{prefix + "KEY_FILE", stringVar(&c.KeyFile)},
+		{prefix + "SERVER_NAME", stringVar(&c.ServerName)},
+		{prefix + "INSECURE_SKIP_VERIFY", boolVar(&c.InsecureSkipVerify)},
+	}
+}
+
+func (c *AuthConfig) envBindings(prefix string) []envBinding {
+	return []envBinding{
+		{prefix + "API_KEY", stringVar(&c.APIKey)},
+		{prefix + "BEARER_TOKEN", stringVar(&c.BearerToken)},
+		{prefix + "ALLOW_INSECURE", boolVar(&c.AllowInsecure)},
+	}
+}
+
+// setModels sets the model aliases from a comma-separated list of alias=name:version pairs. The aliases
+// are added to the ones of the configuration file.
+func (c *Config) setModels(value string) error {
+	if c.Models == nil {
+		c.Models = make(map[string]common.ModelRef)
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		alias, model, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid model alias %q: expected alias=name:version", pair)
+		}
+		i := strings.LastIndex(model, ":")
+		if i < 0 {
+			return fmt.Errorf("invalid model alias %q: expected alias=name:version", pair)
+		}
+		c.Models[strings.TrimSpace(alias)] = common.ModelRef{
+			Name:    strings.TrimSpace(model[:i]),
+			Version: strings.TrimSpace(model[i+1:]),
+		}
+	}
+
+	return nil
+}
+
+func stringVar(field *string) func(string) error {
+	return func(value string) error {
+		*field = value
+		return nil
+	}
+}
+
+func intVar(field *int) func(string) error {
+	return func(value string) error {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field = v
+		return nil
+	}
+}
+
+func boolVar(field *bool) func(string) error {
+	return func(value string) error {
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field = v
+		return nil
+	}
+}
+
+func durationVar(field *Duration) func(string) error {
+	return func(value string) error {
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field = Duration(v)
+		return nil
+	}
+}
@@ -2,20 +2,41 @@ package cliniamodel
 
 import (
 	"context"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
 )
 
 type Embedder interface {
 	// Embed returns the embeddings of the given texts.
-	Embed(ctx context.Context, modelName, modelVersion string, req EmbedRequest) (*EmbedResponse, error)
+	Embed(ctx context.Context, modelName, modelVersion string, req EmbedRequest, opts ...common.CallOption) (*EmbedResponse, error)
 	// Ready checks if the model is ready to receive requests.
 	Ready(ctx context.Context, modelName, modelVersion string) error
+	// Model returns a handle on the given model version.
+	Model(modelName, modelVersion string) EmbedderModel
+	// Alias returns a handle on the model version of the alias, resolved on every call by the model resolver
+	// of the client options.
+	Alias(alias string) EmbedderModel
+}
+
+// EmbedderModel is a handle on a model of the embedder, bound to a model version or to an alias.
+type EmbedderModel interface {
+	// Embed returns the embeddings of the given texts.
+	Embed(ctx context.Context, req EmbedRequest, opts ...common.CallOption) (*EmbedResponse, error)
+	// Ready checks if the model is ready to receive requests.
+	Ready(ctx context.Context) error
 }
 
 type EmbedRequest struct {
-	// ID is the unique identifier for the request.
+	// ID is the unique identifier for the request. If empty, the ID carried by the context (see
+	// common.ContextWithRequestID) is used, or a new one is generated (a random UUIDv4 by default).
 	ID string
 	// Texts is the list of texts to be embedded.
 	Texts []string
+	// Parameters are the optional parameters of the embedder model (e.g. truncation mode or normalization).
+	Parameters common.Parameters
+	// Outputs are the auxiliary outputs to return in addition to the embeddings (e.g. OutputTokenCounts). The
+	// outputs that the model does not declare are not requested, and their fields of the response are left nil.
+	Outputs []AuxiliaryOutput
 }
 
 type EmbedResponse struct {
@@ -23,4 +44,12 @@ type EmbedResponse struct {
 	ID string
 	// Embeddings is the list of embeddings for each text. Each embedding is a list of floats, corresponding to the embedding dimensions. The outer list length matches the number of input texts.
 	Embeddings [][]float32
+	// TokenCounts is the number of tokens of each text, if requested with OutputTokenCounts.
+	TokenCounts []int
+	// Truncated reports whether each text was truncated to the maximum length of the model, if requested with
+	// OutputTruncated.
+	Truncated []bool
+	// TokenEmbeddings is the embedding of each token of each text before pooling, if requested with
+	// OutputTokenEmbeddings. The tokens of each text are padded to the longest text of the batch.
+	TokenEmbeddings [][][]float32
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/clinia/models-client-go/cliniamodel/common"
 	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -14,9 +15,20 @@ const (
 	embedderInputDatatype        = datatype.Bytes
 )
 
+// embedderTensors are the tensors of the embedder models.
+var embedderTensors = clientTensors{
+	inputs:    []tensor{{name: embedderInputKey, datatype: embedderInputDatatype}},
+	outputs:   []tensor{{name: embedderOutputKey, datatype: datatype.Fp32}},
+	auxiliary: []AuxiliaryOutput{OutputTokenCounts, OutputTruncated, OutputTokenEmbeddings},
+}
+
 // embedder is a struct that implements the Embedder interface.
 type embedder struct {
-	requester common.Requester
+	requester  common.Requester
+	tracer     trace.Tracer
+	requestIDs common.RequestIDGenerator
+	models     common.ModelResolver
+	tensors    *tensorResolver
 }
 
 var _ Embedder = (*embedder)(nil)
@@ -24,19 +36,43 @@ var _ Embedder = (*embedder)(nil)
 // NewEmbedder creates a new instance of embedder.
 func NewEmbedder(ctx context.Context, opts common.ClientOptions) Embedder {
 	return &embedder{
-		requester: opts.Requester,
+		requester:  opts.Requester,
+		tracer:     common.Tracer(opts.TracerProvider),
+		requestIDs: opts.RequestIDGenerator,
+		models:     opts.ModelResolver,
+		tensors:    newTensorResolver(opts.Requester, opts.TensorMapping, opts.Logger),
 	}
 }
 
 // Embed generates embeddings for the given texts using the specified model and version.
-func (e *embedder) Embed(ctx context.Context, modelName, modelVersion string, req EmbedRequest) (*EmbedResponse, error) {
+func (e *embedder) Embed(ctx context.Context, modelName, modelVersion string, req EmbedRequest, opts ...common.CallOption) (*EmbedResponse, error) {
+	ctx, cancel := applyCallOptions(ctx, opts, &req.ID, &req.Parameters)
+	defer cancel()
+
+	req.ID = common.RequestID(ctx, req.ID, e.requestIDs)
+	ctx = common.ContextWithRequestID(ctx, req.ID)
+	ctx, span := common.StartModelSpan(ctx, e.tracer, "Embedder.Embed", modelName, modelVersion, len(req.Texts),
+		trace.WithAttributes(common.AttributeRequestID.String(req.ID)))
+	res, err := e.embed(ctx, modelName, modelVersion, req)
+	common.EndSpan(span, err)
+
+	return res, err
+}
+
+func (e *embedder) embed(ctx context.Context, modelName, modelVersion string, req EmbedRequest) (*EmbedResponse, error) {
 	if len(req.Texts) == 0 {
 		return nil, errors.New("texts cannot be empty")
 	}
 
+	// Resolve the tensor names of the model, and the requested auxiliary outputs that it declares.
+	tensors, err := e.tensors.resolve(ctx, modelName, modelVersion, embedderTensors, req.Outputs)
+	if err != nil {
+		return nil, err
+	}
+
 	inputs := []common.Input{
 		{
-			Name:     embedderInputKey,
+			Name:     tensors.name(embedderInputKey),
 			Shape:    []int64{int64(len(req.Texts))},
 			Datatype: embedderInputDatatype,
 			Content: common.Content{
@@ -45,8 +81,7 @@ func (e *embedder) Embed(ctx context.Context, modelName, modelVersion string, re
 		},
 	}
 
-	// The embedder model has only one input and one output.
-	outputKeys := []string{embedderOutputKey}
+	outputKeys := tensors.outputKeys(embedderOutputKey)
 
 	res, err := e.requester.Infer(ctx, common.InferRequest{
 		ID:           req.ID,
@@ -54,25 +89,77 @@ func (e *embedder) Embed(ctx context.Context, modelName, modelVersion string, re
 		ModelVersion: modelVersion,
 		Inputs:       inputs,
 		OutputKeys:   outputKeys,
+		Parameters:   req.Parameters,
 	})
+	if err != nil {
+		e.tensors.invalidateOnError(modelName, modelVersion, err)
+		return nil, err
+	}
+
+	output, err := res.Output(tensors.name(embedderOutputKey))
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings, err := output.Fp32MatrixContent()
 	if err != nil {
 		return nil, err
 	}
 
-	// Since we have only one output, we can directly access the first output.
-	// We already check the size of the output in the infer function therefore we can "safely" access the element 0.
-	embeddings, err := res.Outputs[0].Fp32MatrixContent()
+	aux, err := decodeAuxiliaryOutputs(res, tensors, len(req.Texts))
 	if err != nil {
 		return nil, err
 	}
 
 	return &EmbedResponse{
-		ID:         res.ID,
-		Embeddings: embeddings,
+		ID:              res.ID,
+		Embeddings:      embeddings,
+		TokenCounts:     aux.tokenCounts,
+		Truncated:       aux.truncated,
+		TokenEmbeddings: aux.tokenEmbeddings,
 	}, nil
 }
 
 // Ready implements the Embedder interface. It checks the readiness status of the model.
 func (c *embedder) Ready(ctx context.Context, modelName string, modelVersion string) error {
-	return c.requester.Ready(ctx, modelName, modelVersion)
+	err := c.requester.Ready(ctx, modelName, modelVersion)
+	if err != nil {
+		// The model may be reloaded with other tensors before it is ready again.
+		c.tensors.invalidate(modelName, modelVersion)
+	}
+
+	return err
+}
+
+// Model implements the Embedder interface.
+func (e *embedder) Model(modelName, modelVersion string) EmbedderModel {
+	return &embedderModel{embedder: e, model: fixedModel(modelName, modelVersion)}
+}
+
+// Alias implements the Embedder interface.
+func (e *embedder) Alias(alias string) EmbedderModel {
+	return &embedderModel{embedder: e, model: aliasedModel(e.models, alias)}
+}
+
+// embedderModel is a handle on a model of the embedder.
+type embedderModel struct {
+	embedder *embedder
+	model    modelBinding
+}
+
+var _ EmbedderModel = (*embedderModel)(nil)
+
+// Embed implements the EmbedderModel interface.
+func (m *embedderModel) Embed(ctx context.Context, req EmbedRequest, opts ...common.CallOption) (*EmbedResponse, error) {
+	ref, err := m.model.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.embedder.Embed(ctx, ref.Name, ref.Version, req, opts...)
+}
+
+// Ready implements the EmbedderModel interface. The handles on a routed alias check every version of the route.
+func (m *embedderModel) Ready(ctx context.Context) error {
+	return m.model.ready(ctx, m.embedder.Ready)
 }
@@ -0,0 +1,261 @@
+package cliniamodel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FailoverRule decides whether the error of a model triggers the failover to the next model.
+type FailoverRule func(err error) bool
+
+// DefaultFailoverRule fails over when the model is unreachable, overloaded, not loaded or too slow,
+// but not on invalid requests, which would fail on every model.
+func DefaultFailoverRule(err error) bool {
+	var mismatch *DimensionMismatchError
+	if errors.As(err, &mismatch) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.NotFound, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// FailoverPolicy configures the failover of a model handle to its fallbacks.
+type FailoverPolicy struct {
+	// Rule decides which errors trigger the failover. Defaults to DefaultFailoverRule.
+	Rule FailoverRule
+	// AttemptTimeout bounds the duration of each attempt, so that a model that stopped responding triggers the
+	// failover while the context of the call is still alive. Attempts are not bounded if 0.
+	AttemptTimeout time.Duration
+	// OnFailover is called when the model at index from fails with err and the model at index to is tried next.
+	// The primary model is at index 0 and the fallbacks follow in order.
+	OnFailover func(ctx context.Context, from, to int, err error)
+}
+
+// DimensionMismatchError is returned when a model returns embeddings of a different dimension than the
+// expected one, which would mix incompatible embeddings.
+type DimensionMismatchError struct {
+	// Expected is the expected dimension of the embeddings.
+	Expected int
+	// Actual is the dimension of the embeddings returned by the model.
+	Actual int
+}
+
+func (e *DimensionMismatchError) Error() string {
+	return fmt.Sprintf("embedding dimension %d does not match the expected dimension %d", e.Actual, e.Expected)
+}
+
+// failover calls the models in order until one succeeds or fails with an error that does not trigger the
+// failover. The error of every attempt is returned if all fail.
+func failover[T any](ctx context.Context, policy FailoverPolicy, count int, call func(ctx context.Context, i int) (T, error)) (T, error) {
+	rule := policy.Rule
+	if rule == nil {
+		rule = DefaultFailoverRule
+	}
+
+	var errs []error
+	for i := range count {
+		res, err := attempt(ctx, policy.AttemptTimeout, i, call)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, fmt.Errorf("model %d: %w", i, err))
+
+		// The context of the call is done, the next models would fail as well.
+		if ctx.Err() != nil || !rule(err) || i == count-1 {
+			break
+		}
+		if policy.OnFailover != nil {
+			policy.OnFailover(ctx, i, i+1, err)
+		}
+	}
+
+	var zero T
+	if len(errs) == 1 {
+		return zero, errors.Unwrap(errs[0])
+	}
+	return zero, errors.Join(errs...)
+}
+
+// attempt calls the model at index i, bounded by the timeout if not 0.
+func attempt[T any](ctx context.Context, timeout time.Duration, i int, call func(ctx context.Context, i int) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return call(ctx, i)
+}
+
+// FailoverEmbedder is an EmbedderModel failing over to fallback models, e.g. on another host or another
+// model version, when the primary model is unavailable. Embeddings of different dimensions are never mixed:
+// a model returning embeddings of another dimension than the expected one fails with a DimensionMismatchError.
+type FailoverEmbedder struct {
+	models    []EmbedderModel
+	policy    FailoverPolicy
+	dimension int
+}
+
+var _ EmbedderModel = (*FailoverEmbedder)(nil)
+
+// NewFailoverEmbedder creates an embedder calling the primary model, then the fallbacks in order.
+// The embeddings of every model must have the given dimension, which is required: it cannot be learnt from
+// the responses, since the first one may come from a fallback.
+func NewFailoverEmbedder(dimension int, primary EmbedderModel, fallbacks []EmbedderModel, policy FailoverPolicy) (*FailoverEmbedder, error) {
+	if dimension <= 0 {
+		return nil, fmt.Errorf("invalid embedding dimension %d", dimension)
+	}
+
+	return &FailoverEmbedder{
+		models:    append([]EmbedderModel{primary}, fallbacks...),
+		policy:    policy,
+		dimension: dimension,
+	}, nil
+}
+
+// Embed implements the EmbedderModel interface.
+func (f *FailoverEmbedder) Embed(ctx context.Context, req EmbedRequest, opts ...common.CallOption) (*EmbedResponse, error) {
+	return failover(ctx, f.policy, len(f.models), func(ctx context.Context, i int) (*EmbedResponse, error) {
+		res, err := f.models[i].Embed(ctx, req, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.checkDimension(res.Embeddings); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	})
+}
+
+// checkDimension checks that the embeddings have the expected dimension.
+func (f *FailoverEmbedder) checkDimension(embeddings [][]float32) error {
+	for _, embedding := range embeddings {
+		if len(embedding) != f.dimension {
+			return &DimensionMismatchError{Expected: f.dimension, Actual: len(embedding)}
+		}
+	}
+
+	return nil
+}
+
+// Ready implements the EmbedderModel interface. It succeeds if any of the models is ready.
+func (f *FailoverEmbedder) Ready(ctx context.Context) error {
+	return readyAny(ctx, f.policy, len(f.models), func(ctx context.Context, i int) error {
+		return f.models[i].Ready(ctx)
+	})
+}
+
+// FailoverSparseEmbedder is a SparseEmbedderModel failing over to fallback models when the primary model is
+// unavailable.
+type FailoverSparseEmbedder struct {
+	models []SparseEmbedderModel
+	policy FailoverPolicy
+}
+
+var _ SparseEmbedderModel = (*FailoverSparseEmbedder)(nil)
+
+// NewFailoverSparseEmbedder creates a sparse embedder calling the primary model, then the fallbacks in order.
+func NewFailoverSparseEmbedder(primary SparseEmbedderModel, fallbacks []SparseEmbedderModel, policy FailoverPolicy) *FailoverSparseEmbedder {
+	return &FailoverSparseEmbedder{
+		models: append([]SparseEmbedderModel{primary}, fallbacks...),
+		policy: policy,
+	}
+}
+
+// SparseEmbed implements the SparseEmbedderModel interface.
+func (f *FailoverSparseEmbedder) SparseEmbed(ctx context.Context, req SparseEmbedRequest, opts ...common.CallOption) (*SparseEmbedResponse, error) {
+	return failover(ctx, f.policy, len(f.models), func(ctx context.Context, i int) (*SparseEmbedResponse, error) {
+		return f.models[i].SparseEmbed(ctx, req, opts...)
+	})
+}
+
+// Ready implements the SparseEmbedderModel interface. It succeeds if any of the models is ready.
+func (f *FailoverSparseEmbedder) Ready(ctx context.Context) error {
+	return readyAny(ctx, f.policy, len(f.models), func(ctx context.Context, i int) error {
+		return f.models[i].Ready(ctx)
+	})
+}
+
+// FailoverRanker is a RankerModel failing over to fallback models when the primary model is unavailable.
+type FailoverRanker struct {
+	models []RankerModel
+	policy FailoverPolicy
+}
+
+var _ RankerModel = (*FailoverRanker)(nil)
+
+// NewFailoverRanker creates a ranker calling the primary model, then the fallbacks in order.
+func NewFailoverRanker(primary RankerModel, fallbacks []RankerModel, policy FailoverPolicy) *FailoverRanker {
+	return &FailoverRanker{
+		models: append([]RankerModel{primary}, fallbacks...),
+		policy: policy,
+	}
+}
+
+// Rank implements the RankerModel interface.
+func (f *FailoverRanker) Rank(ctx context.Context, req RankRequest, opts ...common.CallOption) (*RankResponse, error) {
+	return failover(ctx, f.policy, len(f.models), func(ctx context.Context, i int) (*RankResponse, error) {
+		return f.models[i].Rank(ctx, req, opts...)
+	})
+}
+
+// Ready implements the RankerModel interface. It succeeds if any of the models is ready.
+func (f *FailoverRanker) Ready(ctx context.Context) error {
+	return readyAny(ctx, f.policy, len(f.models), func(ctx context.Context, i int) error {
+		return f.models[i].Ready(ctx)
+	})
+}
+
+// FailoverChunker is a ChunkerModel failing over to fallback models when the primary model is unavailable.
+type FailoverChunker struct {
+	models []ChunkerModel
+	policy FailoverPolicy
+}
+
+var _ ChunkerModel = (*FailoverChunker)(nil)
+
+// NewFailoverChunker creates a chunker calling the primary model, then the fallbacks in order.
+func NewFailoverChunker(primary ChunkerModel, fallbacks []ChunkerModel, policy FailoverPolicy) *FailoverChunker {
+	return &FailoverChunker{
+		models: append([]ChunkerModel{primary}, fallbacks...),
+		policy: policy,
+	}
+}
+
+// Chunk implements the ChunkerModel interface.
+func (f *FailoverChunker) Chunk(ctx context.Context, req ChunkRequest, opts ...common.CallOption) (*ChunkResponse, error) {
+	return failover(ctx, f.policy, len(f.models), func(ctx context.Context, i int) (*ChunkResponse, error) {
+		return f.models[i].Chunk(ctx, req, opts...)
+	})
+}
+
+// Ready implements the ChunkerModel interface. It succeeds if any of the models is ready.
+func (f *FailoverChunker) Ready(ctx context.Context) error {
+	return readyAny(ctx, f.policy, len(f.models), func(ctx context.Context, i int) error {
+		return f.models[i].Ready(ctx)
+	})
+}
+
+// readyAny checks the readiness of the models in order until one is ready. Unlike requests, readiness
+// checks fail over on any error, since a model that is not ready is precisely what the fallbacks cover.
+func readyAny(ctx context.Context, policy FailoverPolicy, count int, ready func(ctx context.Context, i int) error) error {
+	policy.Rule = func(error) bool { return true }
+	policy.OnFailover = nil
+	_, err := failover(ctx, policy, count, func(ctx context.Context, i int) (struct{}, error) {
+		return struct{}{}, ready(ctx, i)
+	})
+
+	return err
+}
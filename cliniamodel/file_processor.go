@@ -3,6 +3,7 @@ package cliniamodel
 import (
 	"context"
 
+	"github.com/clinia/models-client-go/cliniamodel/common"
 	"github.com/clinia/models-client-go/cliniamodel/requesterhttp/filesvcclient"
 )
 
@@ -24,6 +25,16 @@ type FileProcessor interface {
 	SplitPDFToImages(
 		ctx context.Context,
 		body PDFSplitRequest,
-		reqEditors ...filesvcclient.RequestEditorFn,
+		opts ...common.CallOption,
 	) (zipBytes []byte, err error)
 }
+
+// WithRequestEditors adds editors of the HTTP requests sent to the file service, applied after the metadata
+// of the call. It is ignored by the model clients.
+func WithRequestEditors(editors ...filesvcclient.RequestEditorFn) common.CallOption {
+	return func(o *common.CallOptions) {
+		for _, editor := range editors {
+			o.RequestEditors = append(o.RequestEditors, editor)
+		}
+	}
+}
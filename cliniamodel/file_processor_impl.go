@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"strconv"
 
+	"github.com/clinia/models-client-go/cliniamodel/common"
 	"github.com/clinia/models-client-go/cliniamodel/requesterhttp/filesvcclient"
 	"github.com/clinia/x/errorx"
 )
@@ -20,8 +23,24 @@ type fileProcessor struct {
 
 var _ FileProcessor = (*fileProcessor)(nil)
 
+// NewFileProcessor creates a file processor for the file service at the given base URL.
+// The base URL is parsed with common.ParseHost, so the file service can also be reached over a
+// Unix domain socket (e.g. "unix:///run/filesvc.sock").
 func NewFileProcessor(baseURL string, opts ...filesvcclient.ClientOption) (FileProcessor, error) {
-	c, err := filesvcclient.NewClient(baseURL, opts...)
+	host, err := common.ParseHost(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	server := host.String()
+	if host.Scheme == common.Unix {
+		// The HTTP client dials the socket regardless of the host of the request URL. The client is
+		// set first so that the other options can wrap it.
+		server = "http://localhost"
+		opts = append([]filesvcclient.ClientOption{filesvcclient.WithHTTPClient(unixHTTPClient(host.Url))}, opts...)
+	}
+
+	c, err := filesvcclient.NewClient(server, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,9 +51,16 @@ func NewFileProcessor(baseURL string, opts ...filesvcclient.ClientOption) (FileP
 func (c fileProcessor) SplitPDFToImages(
 	ctx context.Context,
 	body PDFSplitRequest,
-	reqEditors ...filesvcclient.RequestEditorFn,
+	opts ...common.CallOption,
 ) (zipBytes []byte, err error) {
 	{
+		o := common.NewCallOptions(opts...)
+		if o.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+			defer cancel()
+		}
+
 		if len(body.PDF) == 0 {
 			return nil, errorx.InvalidArgumentError("pdf bytes are empty")
 		}
@@ -67,7 +93,7 @@ func (c fileProcessor) SplitPDFToImages(
 		}
 
 		// Use the generated request helper (no manual URL building)
-		resp, err := c.client.SplitToImagesWithBody(ctx, w.FormDataContentType(), &buf, reqEditors...)
+		resp, err := c.client.SplitToImagesWithBody(ctx, w.FormDataContentType(), &buf, requestEditors(ctx, o)...)
 		if err != nil {
 			return nil, errorx.InternalErrorf("request: %v", err)
 		}
@@ -109,3 +135,46 @@ func (c fileProcessor) SplitPDFToImages(
 		return nil, errorx.InternalErrorf("unexpected status %d, body: %s", resp.StatusCode, string(body))
 	}
 }
+
+// requestEditors translates the call options into editors of the HTTP request. The metadata of the context
+// and of the call are sent as headers, along with the ID of the request if any.
+func requestEditors(ctx context.Context, o common.CallOptions) []filesvcclient.RequestEditorFn {
+	headers := maps.Clone(common.MetadataFromContext(ctx))
+	if headers == nil {
+		headers = make(map[string]string, len(o.Metadata)+1)
+	}
+	maps.Copy(headers, o.Metadata)
+
+	id := o.RequestID
+	if id == "" {
+		id, _ = common.RequestIDFromContext(ctx)
+	}
+	if id != "" {
+		headers[common.MetadataRequestID] = id
+	}
+
+	editors := make([]filesvcclient.RequestEditorFn, 0, len(o.RequestEditors)+1)
+	editors = append(editors, func(_ context.Context, req *http.Request) error {
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		return nil
+	})
+	for _, editor := range o.RequestEditors {
+		editors = append(editors, editor)
+	}
+
+	return editors
+}
+
+// unixHTTPClient returns an HTTP client sending all its requests to the given Unix domain socket.
+func unixHTTPClient(socketPath string) *http.Client {
+	dialer := &net.Dialer{}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+}
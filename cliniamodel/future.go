@@ -0,0 +1,136 @@
+package cliniamodel
+
+import (
+	"context"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// Awaitable is the part of a Future that does not depend on its result type, so that futures of different
+// types can be combined with All.
+type Awaitable interface {
+	// Done returns a channel closed once the operation has completed.
+	Done() <-chan struct{}
+	// Err returns the error of the operation, once completed.
+	Err() error
+	// Cancel cancels the operation.
+	Cancel()
+}
+
+// Future is the result of an operation running in the background.
+type Future[T any] struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	res    T
+	err    error
+}
+
+var _ Awaitable = (*Future[any])(nil)
+
+// Async runs the operation in the background and returns its future. The operation is given a context
+// derived from ctx, which is canceled by Cancel.
+func Async[T any](ctx context.Context, op func(ctx context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Future[T]{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer close(f.done)
+		defer cancel()
+
+		f.res, f.err = op(ctx)
+	}()
+
+	return f
+}
+
+// Wait waits for the operation to complete and returns its result. If ctx is done first, its error is
+// returned and the operation keeps running; use Cancel to stop it.
+func (f *Future[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.res, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done implements Awaitable.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Err implements Awaitable. It returns nil while the operation is running.
+func (f *Future[T]) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Cancel implements Awaitable.
+func (f *Future[T]) Cancel() {
+	f.cancel()
+}
+
+// All waits for all the futures to complete. If one of them fails, the others are canceled and its error is
+// returned. If ctx is done first, all the futures are canceled and the error of ctx is returned.
+// Once All returns nil, the results are available without blocking through the Wait method of each future.
+func All(ctx context.Context, futures ...Awaitable) error {
+	// Wait for the futures in completion order, so that the first failure cancels the others right away.
+	// The channel is buffered so that the goroutines never block once All has returned.
+	completed := make(chan Awaitable, len(futures))
+	for _, f := range futures {
+		go func() {
+			<-f.Done()
+			completed <- f
+		}()
+	}
+
+	for range futures {
+		select {
+		case f := <-completed:
+			if err := f.Err(); err != nil {
+				cancelAll(futures)
+				return err
+			}
+		case <-ctx.Done():
+			cancelAll(futures)
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func cancelAll(futures []Awaitable) {
+	for _, f := range futures {
+		f.Cancel()
+	}
+}
+
+// EmbedAsync embeds the texts of the request with the model in the background.
+func EmbedAsync(ctx context.Context, model EmbedderModel, req EmbedRequest, opts ...common.CallOption) *Future[*EmbedResponse] {
+	return Async(ctx, func(ctx context.Context) (*EmbedResponse, error) {
+		return model.Embed(ctx, req, opts...)
+	})
+}
+
+// SparseEmbedAsync sparse embeds the texts of the request with the model in the background.
+func SparseEmbedAsync(ctx context.Context, model SparseEmbedderModel, req SparseEmbedRequest, opts ...common.CallOption) *Future[*SparseEmbedResponse] {
+	return Async(ctx, func(ctx context.Context) (*SparseEmbedResponse, error) {
+		return model.SparseEmbed(ctx, req, opts...)
+	})
+}
+
+// RankAsync ranks the texts of the request with the model in the background.
+func RankAsync(ctx context.Context, model RankerModel, req RankRequest, opts ...common.CallOption) *Future[*RankResponse] {
+	return Async(ctx, func(ctx context.Context) (*RankResponse, error) {
+		return model.Rank(ctx, req, opts...)
+	})
+}
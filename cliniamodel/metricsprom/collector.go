@@ -0,0 +1,117 @@
+// Package metricsprom provides a Prometheus implementation of common.MetricsRecorder.
+package metricsprom
+
+import (
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "cliniamodel"
+
+var (
+	requestLabels  = []string{"operation", "model_name", "model_version", "outcome"}
+	modelLabels    = []string{"operation", "model_name", "model_version"}
+	routeLabels    = []string{"alias", "model_name", "model_version"}
+	batchBuckets   = prometheus.ExponentialBuckets(1, 2, 10)
+	payloadBuckets = prometheus.ExponentialBuckets(1024, 4, 10)
+)
+
+// Collector records the metrics of the inference traffic in Prometheus.
+type Collector struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	batchSize   *prometheus.HistogramVec
+	payloadSize *prometheus.HistogramVec
+	inFlight    *prometheus.GaugeVec
+	retries     *prometheus.CounterVec
+	routes      *prometheus.CounterVec
+}
+
+var _ common.MetricsRecorder = (*Collector)(nil)
+
+// NewCollector creates a new collector and registers its metrics against the given registry.
+func NewCollector(reg prometheus.Registerer) (*Collector, error) {
+	c := &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of requests made to the model servers.",
+		}, requestLabels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the requests made to the model servers.",
+			Buckets:   prometheus.DefBuckets,
+		}, requestLabels),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_batch_size",
+			Help:      "Number of elements sent in the requests made to the model servers.",
+			Buckets:   batchBuckets,
+		}, modelLabels),
+		payloadSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_payload_bytes",
+			Help:      "Size of the payloads sent in the requests made to the model servers.",
+			Buckets:   payloadBuckets,
+		}, modelLabels),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "requests_in_flight",
+			Help:      "Number of requests in flight to the model servers.",
+		}, modelLabels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Number of retries of the requests made to the model servers.",
+		}, modelLabels),
+		routes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "routes_total",
+			Help:      "Number of requests on a model alias routed to each model version.",
+		}, routeLabels),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		c.requests,
+		c.duration,
+		c.batchSize,
+		c.payloadSize,
+		c.inFlight,
+		c.retries,
+		c.routes,
+	} {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// ObserveRequest implements common.MetricsRecorder.
+func (c *Collector) ObserveRequest(obs common.RequestObservation) {
+	c.requests.WithLabelValues(obs.Operation, obs.ModelName, obs.ModelVersion, obs.Outcome).Inc()
+	c.duration.WithLabelValues(obs.Operation, obs.ModelName, obs.ModelVersion, obs.Outcome).Observe(obs.Duration.Seconds())
+	if obs.BatchSize > 0 {
+		c.batchSize.WithLabelValues(obs.Operation, obs.ModelName, obs.ModelVersion).Observe(float64(obs.BatchSize))
+	}
+	if obs.PayloadBytes > 0 {
+		c.payloadSize.WithLabelValues(obs.Operation, obs.ModelName, obs.ModelVersion).Observe(float64(obs.PayloadBytes))
+	}
+}
+
+// AddInFlight implements common.MetricsRecorder.
+func (c *Collector) AddInFlight(operation, modelName, modelVersion string, delta int) {
+	c.inFlight.WithLabelValues(operation, modelName, modelVersion).Add(float64(delta))
+}
+
+// ObserveRetry implements common.MetricsRecorder.
+func (c *Collector) ObserveRetry(operation, modelName, modelVersion string) {
+	c.retries.WithLabelValues(operation, modelName, modelVersion).Inc()
+}
+
+// ObserveRoute implements common.MetricsRecorder.
+func (c *Collector) ObserveRoute(alias, modelName, modelVersion string) {
+	c.routes.WithLabelValues(alias, modelName, modelVersion).Inc()
+}
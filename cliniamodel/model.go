@@ -0,0 +1,68 @@
+package cliniamodel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// errNoModelResolver is returned by the handles bound to an alias when the client has no model resolver.
+var errNoModelResolver = errors.New("no model resolver configured")
+
+// modelBinding resolves the model version of a model handle, on every call.
+type modelBinding struct {
+	// resolve returns the model version a request is made to.
+	resolve func(ctx context.Context) (common.ModelRef, error)
+	// versions returns every model version the requests may be made to, without routing a request.
+	versions func(ctx context.Context) ([]common.ModelRef, error)
+}
+
+// fixedModel binds a handle to the given model version.
+func fixedModel(modelName, modelVersion string) modelBinding {
+	ref := common.ModelRef{Name: modelName, Version: modelVersion}
+	return modelBinding{
+		resolve: func(context.Context) (common.ModelRef, error) {
+			return ref, nil
+		},
+		versions: func(context.Context) ([]common.ModelRef, error) {
+			return []common.ModelRef{ref}, nil
+		},
+	}
+}
+
+// aliasedModel binds a handle to the model version of the alias, as resolved by the resolver.
+func aliasedModel(resolver common.ModelResolver, alias string) modelBinding {
+	return modelBinding{
+		resolve: func(ctx context.Context) (common.ModelRef, error) {
+			if resolver == nil {
+				return common.ModelRef{}, errNoModelResolver
+			}
+
+			return resolver.Resolve(ctx, alias)
+		},
+		versions: func(ctx context.Context) ([]common.ModelRef, error) {
+			if resolver == nil {
+				return nil, errNoModelResolver
+			}
+
+			return common.ResolveVersions(ctx, resolver, alias)
+		},
+	}
+}
+
+// ready checks the readiness of every model version the handle may make requests to.
+func (b modelBinding) ready(ctx context.Context, ready func(ctx context.Context, modelName, modelVersion string) error) error {
+	refs, err := b.versions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		if err := ready(ctx, ref.Name, ref.Version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package cliniamodel
+
+import (
+	"fmt"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// AuxiliaryOutput is an optional output of a model, returned in addition to its main output. Its value is the
+// default name of the output tensor, which can be mapped with the tensor mapping of the client options.
+type AuxiliaryOutput string
+
+const (
+	// OutputTokenCounts is the number of tokens of each text (INT32, of shape [batch] or [batch, 1]).
+	OutputTokenCounts AuxiliaryOutput = "token_count"
+	// OutputTruncated reports whether each text was truncated to the maximum length of the model (BOOL, of
+	// shape [batch] or [batch, 1]).
+	OutputTruncated AuxiliaryOutput = "truncated"
+	// OutputTokenEmbeddings is the embedding of each token of each text, before pooling (FP32, of shape
+	// [batch, tokens, dimension]).
+	OutputTokenEmbeddings AuxiliaryOutput = "token_embedding"
+)
+
+// auxiliaryOutputs are the decoded auxiliary outputs of a response.
+type auxiliaryOutputs struct {
+	tokenCounts     []int
+	truncated       []bool
+	tokenEmbeddings [][][]float32
+}
+
+// decodeAuxiliaryOutputs decodes the auxiliary outputs of the response, each of which must have one element
+// per text of the batch.
+func decodeAuxiliaryOutputs(res *common.InferResponse, tensors modelTensors, batchSize int) (auxiliaryOutputs, error) {
+	var decoded auxiliaryOutputs
+	for _, name := range tensors.auxiliary {
+		output, err := res.Output(tensors.name(string(name)))
+		if err != nil {
+			return auxiliaryOutputs{}, err
+		}
+
+		var count int
+		switch name {
+		case OutputTokenCounts:
+			tokenCounts, err := output.Int32VectorContent()
+			if err != nil {
+				return auxiliaryOutputs{}, fmt.Errorf("output %s: %w", name, err)
+			}
+			decoded.tokenCounts = make([]int, len(tokenCounts))
+			for i, tokenCount := range tokenCounts {
+				decoded.tokenCounts[i] = int(tokenCount)
+			}
+			count = len(tokenCounts)
+		case OutputTruncated:
+			decoded.truncated, err = output.BoolVectorContent()
+			if err != nil {
+				return auxiliaryOutputs{}, fmt.Errorf("output %s: %w", name, err)
+			}
+			count = len(decoded.truncated)
+		case OutputTokenEmbeddings:
+			decoded.tokenEmbeddings, err = output.Fp32TensorContent()
+			if err != nil {
+				return auxiliaryOutputs{}, fmt.Errorf("output %s: %w", name, err)
+			}
+			count = len(decoded.tokenEmbeddings)
+		default:
+			return auxiliaryOutputs{}, fmt.Errorf("unsupported auxiliary output: %s", name)
+		}
+
+		if count != batchSize {
+			return auxiliaryOutputs{}, fmt.Errorf("output %s: expected %d elements, got %d", name, batchSize, count)
+		}
+	}
+
+	return decoded, nil
+}
@@ -1,21 +1,44 @@
 package cliniamodel
 
-import "context"
+import (
+	"context"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
 
 type Ranker interface {
 	// Rank returns the ranked results of the given texts.
-	Rank(ctx context.Context, modelName, modelVersion string, req RankRequest) (*RankResponse, error)
+	Rank(ctx context.Context, modelName, modelVersion string, req RankRequest, opts ...common.CallOption) (*RankResponse, error)
 	// Ready checks if the model is ready to receive requests.
 	Ready(ctx context.Context, modelName, modelVersion string) error
+	// Model returns a handle on the given model version.
+	Model(modelName, modelVersion string) RankerModel
+	// Alias returns a handle on the model version of the alias, resolved on every call by the model resolver
+	// of the client options.
+	Alias(alias string) RankerModel
+}
+
+// RankerModel is a handle on a model of the ranker, bound to a model version or to an alias.
+type RankerModel interface {
+	// Rank returns the ranked results of the given texts.
+	Rank(ctx context.Context, req RankRequest, opts ...common.CallOption) (*RankResponse, error)
+	// Ready checks if the model is ready to receive requests.
+	Ready(ctx context.Context) error
 }
 
 type RankRequest struct {
-	// ID is the unique identifier for the request. If not provided, a random UUIDv4 will be generated.
+	// ID is the unique identifier for the request. If empty, the ID carried by the context (see
+	// common.ContextWithRequestID) is used, or a new one is generated (a random UUIDv4 by default).
 	ID string
 	// Query is the query to rank the passages against.
 	Query string
 	// Texts is the list of passages to be ranked.
 	Texts []string
+	// Parameters are the optional parameters of the ranker model (e.g. truncation mode).
+	Parameters common.Parameters
+	// Outputs are the auxiliary outputs to return in addition to the scores (e.g. OutputTokenCounts). The
+	// outputs that the model does not declare are not requested, and their fields of the response are left nil.
+	Outputs []AuxiliaryOutput
 }
 
 type RankResponse struct {
@@ -23,4 +46,9 @@ type RankResponse struct {
 	ID string
 	// Scores is the list of scores for each pair of query and passage.
 	Scores []float32
+	// TokenCounts is the number of tokens of each query and text pair, if requested with OutputTokenCounts.
+	TokenCounts []int
+	// Truncated reports whether each query and text pair was truncated to the maximum length of the model, if
+	// requested with OutputTruncated.
+	Truncated []bool
 }
@@ -8,22 +8,36 @@ import (
 
 	"github.com/clinia/models-client-go/cliniamodel/common"
 	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	rankerQueryInputKey      string            = "query"
 	rankerQueryInputDatatype datatype.Datatype = datatype.Bytes
 
-	// TODO: Change to text
 	rankerPassageInputKey      string            = "text"
 	rankerPassageInputDatatype datatype.Datatype = datatype.Bytes
 
 	rankerScoreOutputKey string = "score"
 )
 
+// rankerTensors are the tensors of the ranker models.
+var rankerTensors = clientTensors{
+	inputs: []tensor{
+		{name: rankerQueryInputKey, datatype: rankerQueryInputDatatype},
+		{name: rankerPassageInputKey, datatype: rankerPassageInputDatatype},
+	},
+	outputs:   []tensor{{name: rankerScoreOutputKey, datatype: datatype.Fp32}},
+	auxiliary: []AuxiliaryOutput{OutputTokenCounts, OutputTruncated},
+}
+
 // ranker is a struct that implements the Ranker interface.
 type ranker struct {
-	requester common.Requester
+	requester  common.Requester
+	tracer     trace.Tracer
+	requestIDs common.RequestIDGenerator
+	models     common.ModelResolver
+	tensors    *tensorResolver
 }
 
 var _ Ranker = (*ranker)(nil)
@@ -31,7 +45,11 @@ var _ Ranker = (*ranker)(nil)
 // NewRanker creates a new instance of ranker with the provided options.
 func NewRanker(opts common.ClientOptions) Ranker {
 	return &ranker{
-		requester: opts.Requester,
+		requester:  opts.Requester,
+		tracer:     common.Tracer(opts.TracerProvider),
+		requestIDs: opts.RequestIDGenerator,
+		models:     opts.ModelResolver,
+		tensors:    newTensorResolver(opts.Requester, opts.TensorMapping, opts.Logger),
 	}
 }
 
@@ -39,7 +57,21 @@ func NewRanker(opts common.ClientOptions) Ranker {
 // and returns a RankResponse or an error. The function duplicates the query to match the size of the texts,
 // prepares the inputs, and calls the infer function of the requester. It then processes the output to
 // return the scores.
-func (r *ranker) Rank(ctx context.Context, modelName string, modelVersion string, req RankRequest) (*RankResponse, error) {
+func (r *ranker) Rank(ctx context.Context, modelName string, modelVersion string, req RankRequest, opts ...common.CallOption) (*RankResponse, error) {
+	ctx, cancel := applyCallOptions(ctx, opts, &req.ID, &req.Parameters)
+	defer cancel()
+
+	req.ID = common.RequestID(ctx, req.ID, r.requestIDs)
+	ctx = common.ContextWithRequestID(ctx, req.ID)
+	ctx, span := common.StartModelSpan(ctx, r.tracer, "Ranker.Rank", modelName, modelVersion, len(req.Texts),
+		trace.WithAttributes(common.AttributeRequestID.String(req.ID)))
+	res, err := r.rank(ctx, modelName, modelVersion, req)
+	common.EndSpan(span, err)
+
+	return res, err
+}
+
+func (r *ranker) rank(ctx context.Context, modelName, modelVersion string, req RankRequest) (*RankResponse, error) {
 	if strings.TrimSpace(req.Query) == "" {
 		return nil, errors.New("query must not be empty")
 	}
@@ -48,6 +80,12 @@ func (r *ranker) Rank(ctx context.Context, modelName string, modelVersion string
 		return nil, errors.New("texts cannot be empty")
 	}
 
+	// Resolve the tensor names of the model, and the requested auxiliary outputs that it declares.
+	tensors, err := r.tensors.resolve(ctx, modelName, modelVersion, rankerTensors, req.Outputs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Duplicate query to be the same size as texts
 	inputQueries := make([]string, len(req.Texts))
 	for i := range req.Texts {
@@ -58,14 +96,14 @@ func (r *ranker) Rank(ctx context.Context, modelName string, modelVersion string
 	// when transforming the string content to the raw input.
 	inputs := []common.Input{
 		{
-			Name:     rankerQueryInputKey,
+			Name:     tensors.name(rankerQueryInputKey),
 			Datatype: rankerQueryInputDatatype,
 			Content: common.Content{
 				StringContents: inputQueries,
 			},
 		},
 		{
-			Name:     rankerPassageInputKey,
+			Name:     tensors.name(rankerPassageInputKey),
 			Datatype: rankerPassageInputDatatype,
 			Content: common.Content{
 				StringContents: req.Texts,
@@ -73,8 +111,7 @@ func (r *ranker) Rank(ctx context.Context, modelName string, modelVersion string
 		},
 	}
 
-	// The ranker model has only one input and one output.
-	outputKeys := []string{rankerScoreOutputKey}
+	outputKeys := tensors.outputKeys(rankerScoreOutputKey)
 
 	res, err := r.requester.Infer(ctx, common.InferRequest{
 		ID:           req.ID,
@@ -82,14 +119,19 @@ func (r *ranker) Rank(ctx context.Context, modelName string, modelVersion string
 		ModelVersion: modelVersion,
 		Inputs:       inputs,
 		OutputKeys:   outputKeys,
+		Parameters:   req.Parameters,
 	})
+	if err != nil {
+		r.tensors.invalidateOnError(modelName, modelVersion, err)
+		return nil, err
+	}
+
+	output, err := res.Output(tensors.name(rankerScoreOutputKey))
 	if err != nil {
 		return nil, err
 	}
 
-	// Since we have only one output, we can directly access the first output.
-	// We already check the size of the output in the infer function.
-	scores, err := res.Outputs[0].Fp32MatrixContent()
+	scores, err := output.Fp32MatrixContent()
 	if err != nil {
 		return nil, err
 	}
@@ -102,13 +144,60 @@ func (r *ranker) Rank(ctx context.Context, modelName string, modelVersion string
 		}
 		flattenedScores = append(flattenedScores, score...)
 	}
+
+	aux, err := decodeAuxiliaryOutputs(res, tensors, len(req.Texts))
+	if err != nil {
+		return nil, err
+	}
+
 	return &RankResponse{
-		ID:     res.ID,
-		Scores: flattenedScores,
+		ID:          res.ID,
+		Scores:      flattenedScores,
+		TokenCounts: aux.tokenCounts,
+		Truncated:   aux.truncated,
 	}, nil
 }
 
 // Ready implements the Ranker interface. It checks the readiness status of the model.
 func (c *ranker) Ready(ctx context.Context, modelName string, modelVersion string) error {
-	return c.requester.Ready(ctx, modelName, modelVersion)
+	err := c.requester.Ready(ctx, modelName, modelVersion)
+	if err != nil {
+		// The model may be reloaded with other tensors before it is ready again.
+		c.tensors.invalidate(modelName, modelVersion)
+	}
+
+	return err
+}
+
+// Model implements the Ranker interface.
+func (r *ranker) Model(modelName, modelVersion string) RankerModel {
+	return &rankerModel{ranker: r, model: fixedModel(modelName, modelVersion)}
+}
+
+// Alias implements the Ranker interface.
+func (r *ranker) Alias(alias string) RankerModel {
+	return &rankerModel{ranker: r, model: aliasedModel(r.models, alias)}
+}
+
+// rankerModel is a handle on a model of the ranker.
+type rankerModel struct {
+	ranker *ranker
+	model  modelBinding
+}
+
+var _ RankerModel = (*rankerModel)(nil)
+
+// Rank implements the RankerModel interface.
+func (m *rankerModel) Rank(ctx context.Context, req RankRequest, opts ...common.CallOption) (*RankResponse, error) {
+	ref, err := m.model.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ranker.Rank(ctx, ref.Name, ref.Version, req, opts...)
+}
+
+// Ready implements the RankerModel interface. The handles on a routed alias check every version of the route.
+func (m *rankerModel) Ready(ctx context.Context) error {
+	return m.model.ready(ctx, m.ranker.Ready)
 }
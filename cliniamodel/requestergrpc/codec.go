@@ -0,0 +1,150 @@
+package requestergrpc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unsafe"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// nativeLittleEndian reports whether the host stores integers and floats in little endian order,
+// in which case the FP32 tensors can be decoded with a single memory copy.
+var nativeLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// encodeStrings serializes the strings into a BYTES tensor, where each element is prefixed by its length
+// as a 4-byte little endian integer. The buffer is allocated once, at its final size.
+func encodeStrings(texts []string) ([]byte, error) {
+	size := 0
+	for i, text := range texts {
+		if uint64(len(text)) > math.MaxUint32 {
+			return nil, fmt.Errorf("element %d of %d bytes exceeds the maximum size of a BYTES element", i, len(text))
+		}
+		size += 4 + len(text)
+	}
+
+	buf := make([]byte, size)
+	offset := 0
+	for _, text := range texts {
+		// #nosec G115 -- the length was checked above.
+		binary.LittleEndian.PutUint32(buf[offset:], uint32(len(text)))
+		offset += 4
+		offset += copy(buf[offset:], text)
+	}
+
+	return buf, nil
+}
+
+// decodeFloat32 decodes the FP32 tensor of the given name into a float32 array.
+func decodeFloat32(name string, encodedTensor []byte) ([]float32, error) {
+	if len(encodedTensor)%4 != 0 {
+		return nil, &common.TensorDecodeError{Tensor: name, Index: len(encodedTensor) / 4, Err: common.ErrTruncatedTensor}
+	}
+
+	floats := make([]float32, len(encodedTensor)/4)
+	if len(floats) == 0 {
+		return floats, nil
+	}
+
+	if nativeLittleEndian {
+		// The tensor has the memory layout of the float array, copy it in bulk.
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&floats[0])), len(encodedTensor)), encodedTensor)
+		return floats, nil
+	}
+
+	for i := range floats {
+		floats[i] = math.Float32frombits(binary.LittleEndian.Uint32(encodedTensor[i*4:]))
+	}
+
+	return floats, nil
+}
+
+// decodeInt32 decodes the INT32 tensor of the given name into an int32 array.
+func decodeInt32(name string, encodedTensor []byte) ([]int32, error) {
+	if len(encodedTensor)%4 != 0 {
+		return nil, &common.TensorDecodeError{Tensor: name, Index: len(encodedTensor) / 4, Err: common.ErrTruncatedTensor}
+	}
+
+	ints := make([]int32, len(encodedTensor)/4)
+	for i := range ints {
+		// #nosec G115 -- the bits are reinterpreted as a two's complement integer.
+		ints[i] = int32(binary.LittleEndian.Uint32(encodedTensor[i*4:]))
+	}
+
+	return ints, nil
+}
+
+// decodeBool decodes the BOOL tensor of the given name into a bool array, where each element is a single byte.
+func decodeBool(name string, encodedTensor []byte) ([]bool, error) {
+	bools := make([]bool, len(encodedTensor))
+	for i, b := range encodedTensor {
+		switch b {
+		case 0:
+		case 1:
+			bools[i] = true
+		default:
+			return nil, &common.TensorDecodeError{Tensor: name, Index: i, Err: common.ErrInvalidBool}
+		}
+	}
+
+	return bools, nil
+}
+
+// decodeString decodes the BYTES tensor of the given name into a string array. Every length prefix is
+// validated against the remaining bytes of the tensor and against maxElementBytes.
+func decodeString(name string, encodedTensor []byte, maxElementBytes int) ([]string, error) {
+	// Validate the elements and count them first to allocate the array once.
+	count := 0
+	for offset := 0; offset < len(encodedTensor); count++ {
+		if len(encodedTensor)-offset < 4 {
+			return nil, &common.TensorDecodeError{Tensor: name, Index: count, Err: common.ErrTruncatedTensor}
+		}
+		length := uint64(binary.LittleEndian.Uint32(encodedTensor[offset:]))
+		offset += 4
+
+		if maxElementBytes < 0 || length > uint64(maxElementBytes) {
+			return nil, &common.TensorDecodeError{Tensor: name, Index: count, Err: common.ErrElementTooLarge}
+		}
+		if length > uint64(len(encodedTensor)-offset) {
+			return nil, &common.TensorDecodeError{Tensor: name, Index: count, Err: common.ErrTruncatedTensor}
+		}
+		offset += int(length)
+	}
+
+	// The whole tensor is converted to a single string, of which the elements are substrings.
+	// This trades one allocation per element for one allocation per tensor.
+	tensor := string(encodedTensor)
+	strs := make([]string, 0, count)
+	offset := 0
+	for offset < len(tensor) {
+		length := int(binary.LittleEndian.Uint32(encodedTensor[offset:]))
+		offset += 4
+
+		strs = append(strs, tensor[offset:offset+length])
+		offset += length
+	}
+
+	return strs, nil
+}
+
+// checkElementCount checks that the number of decoded elements of the tensor of the given name matches
+// its shape. Shapes with dynamic (negative) dimensions are not checked.
+func checkElementCount(name string, shape []int64, count int) error {
+	expected := int64(1)
+	for _, dim := range shape {
+		if dim < 0 {
+			return nil
+		}
+		expected *= dim
+	}
+
+	if expected != int64(count) {
+		return &common.TensorDecodeError{Tensor: name, Index: -1, Err: common.ErrElementCount}
+	}
+
+	return nil
+}
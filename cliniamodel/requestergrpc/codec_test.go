@@ -0,0 +1,201 @@
+package requestergrpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// benchmarkTexts returns a batch of texts of the given size and length.
+func benchmarkTexts(batchSize, length int) []string {
+	texts := make([]string, batchSize)
+	for i := range texts {
+		texts[i] = strings.Repeat("a", length)
+	}
+
+	return texts
+}
+
+// encodeStringsBaseline is the encoding of BYTES tensors before buffers were pre-sized, kept for comparison.
+func encodeStringsBaseline(texts []string) []byte {
+	var buf bytes.Buffer
+	for _, text := range texts {
+		// #nosec G115 -- the texts of the benchmarks are small.
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(text)))
+		buf.Write([]byte(text))
+	}
+
+	return buf.Bytes()
+}
+
+// decodeFloat32Baseline is the decoding of FP32 tensors before the bulk copy, kept for comparison.
+func decodeFloat32Baseline(encodedTensor []byte) []float32 {
+	var floats []float32
+	for offset := 0; offset+4 <= len(encodedTensor); offset += 4 {
+		floats = append(floats, math.Float32frombits(binary.LittleEndian.Uint32(encodedTensor[offset:])))
+	}
+
+	return floats
+}
+
+// decodeStringBaseline is the decoding of BYTES tensors before the single allocation, kept for comparison.
+func decodeStringBaseline(encodedTensor []byte) []string {
+	var strs []string
+	for offset := 0; offset < len(encodedTensor); {
+		length := int(binary.LittleEndian.Uint32(encodedTensor[offset:]))
+		offset += 4
+		strs = append(strs, string(encodedTensor[offset:offset+length]))
+		offset += length
+	}
+
+	return strs
+}
+
+func BenchmarkEncodeStrings(b *testing.B) {
+	texts := benchmarkTexts(256, 512)
+
+	b.Run("baseline", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			encodeStringsBaseline(texts)
+		}
+	})
+	b.Run("presized", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := encodeStrings(texts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeFloat32(b *testing.B) {
+	// A batch of 256 embeddings of 1024 dimensions.
+	encodedTensor := make([]byte, 256*1024*4)
+	for i := 0; i < len(encodedTensor); i += 4 {
+		binary.LittleEndian.PutUint32(encodedTensor[i:], math.Float32bits(float32(i)))
+	}
+
+	b.Run("baseline", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			decodeFloat32Baseline(encodedTensor)
+		}
+	})
+	b.Run("bulk", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := decodeFloat32("embedding", encodedTensor); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeString(b *testing.B) {
+	encodedTensor, err := encodeStrings(benchmarkTexts(256, 512))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("baseline", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			decodeStringBaseline(encodedTensor)
+		}
+	})
+	b.Run("single-allocation", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := decodeString("chunk", encodedTensor, math.MaxInt32); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func FuzzDecodeString(f *testing.F) {
+	valid, err := encodeStrings([]string{"clinia", "", "montreal"})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(valid, 64)
+	// Truncated length prefix.
+	f.Add(valid[:len(valid)-len("montreal")-2], 64)
+	// Truncated element.
+	f.Add(valid[:len(valid)-1], 64)
+	// Length prefix exceeding the remaining bytes.
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 'a'}, math.MaxInt32)
+	// Element exceeding the element size limit.
+	f.Add(valid, 4)
+	f.Add([]byte{}, 0)
+
+	f.Fuzz(func(t *testing.T, encodedTensor []byte, maxElementBytes int) {
+		strs, err := decodeString("text", encodedTensor, maxElementBytes)
+		if err != nil {
+			var decodeErr *common.TensorDecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected a TensorDecodeError, got %v", err)
+			}
+			return
+		}
+
+		for i, str := range strs {
+			if len(str) > maxElementBytes {
+				t.Fatalf("element %d of %d bytes exceeds the limit of %d bytes", i, len(str), maxElementBytes)
+			}
+		}
+
+		// A decoded tensor is encoded back to the same bytes.
+		reencoded, err := encodeStrings(strs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(reencoded, encodedTensor) {
+			t.Fatalf("re-encoded tensor %x differs from %x", reencoded, encodedTensor)
+		}
+	})
+}
+
+func FuzzEncodeDecode(f *testing.F) {
+	f.Add("clinia\x00montreal", 0)
+	f.Add("", 0)
+	f.Add("\x00\x00", 0)
+	// Shape not matching the number of elements.
+	f.Add("clinia\x00montreal", 1)
+	f.Add("clinia", -1)
+
+	f.Fuzz(func(t *testing.T, joined string, countDelta int) {
+		texts := strings.Split(joined, "\x00")
+
+		encodedTensor, err := encodeStrings(texts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		strs, err := decodeString("text", encodedTensor, math.MaxInt32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(strs, texts) {
+			t.Fatalf("decoded %q, expected %q", strs, texts)
+		}
+
+		shape := []int64{int64(len(texts) + countDelta), 1}
+		err = checkElementCount("text", shape, len(strs))
+		if countDelta == 0 && err != nil {
+			t.Fatalf("unexpected error for a matching shape: %v", err)
+		}
+		// Negative dimensions are dynamic and never checked.
+		if countDelta != 0 && shape[0] >= 0 && !errors.Is(err, common.ErrElementCount) {
+			t.Fatalf("expected ErrElementCount for shape %v and %d elements, got %v", shape, len(strs), err)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package requestergrpc
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/stats"
+)
+
+// observation instruments a request made to the model server.
+type observation struct {
+	r     *requester
+	ctx   context.Context
+	span  trace.Span
+	start time.Time
+
+	operation    string
+	modelName    string
+	modelVersion string
+	batchSize    int
+	payloadBytes int
+
+	// requestID and inputs are logged along with slow requests, when known.
+	requestID string
+	inputs    []common.Input
+
+	// attempts is the number of attempts of the RPC made for the request, counted by retryStatsHandler.
+	attempts atomic.Int32
+}
+
+// observationKey is the context key of the observation of the request an RPC is made for.
+type observationKey struct{}
+
+// observe starts the instrumentation of a request on the given model version.
+// The returned observation must be ended once the request completes.
+func (r *requester) observe(ctx context.Context, operation string, spanName string, modelName, modelVersion string, batchSize int) (context.Context, *observation) {
+	ctx, span := common.StartModelSpan(ctx, r.tracer, spanName, modelName, modelVersion, batchSize, trace.WithSpanKind(trace.SpanKindClient))
+	r.metrics.AddInFlight(operation, modelName, modelVersion, 1)
+
+	obs := &observation{
+		r:            r,
+		ctx:          ctx,
+		span:         span,
+		start:        time.Now(),
+		operation:    operation,
+		modelName:    modelName,
+		modelVersion: modelVersion,
+		batchSize:    batchSize,
+	}
+
+	return context.WithValue(ctx, observationKey{}, obs), obs
+}
+
+// setPayloadBytes sets the size of the payload sent in the request.
+func (o *observation) setPayloadBytes(payloadBytes int) {
+	o.payloadBytes = payloadBytes
+	o.span.SetAttributes(common.AttributePayloadBytes.Int(payloadBytes))
+}
+
+// end records the outcome of the request.
+func (o *observation) end(err error) {
+	duration := time.Since(o.start)
+
+	o.r.metrics.AddInFlight(o.operation, o.modelName, o.modelVersion, -1)
+	o.r.metrics.ObserveRequest(common.RequestObservation{
+		Operation:    o.operation,
+		ModelName:    o.modelName,
+		ModelVersion: o.modelVersion,
+		Outcome:      common.Outcome(err),
+		Duration:     duration,
+		BatchSize:    o.batchSize,
+		PayloadBytes: o.payloadBytes,
+	})
+	o.log(duration, err)
+	common.EndSpan(o.span, err)
+}
+
+// log logs the completion of the request. Failed requests are logged at the debug level since
+// their error is returned to the caller; slow requests are logged as warnings.
+func (o *observation) log(duration time.Duration, err error) {
+	attrs := []slog.Attr{
+		slog.String("operation", o.operation),
+		slog.String("model_name", o.modelName),
+		slog.String("model_version", o.modelVersion),
+		slog.Int("batch_size", o.batchSize),
+		slog.Int("payload_bytes", o.payloadBytes),
+		slog.Duration("duration", duration),
+	}
+	if o.requestID != "" {
+		attrs = append(attrs, slog.String("request_id", o.requestID))
+	}
+
+	switch {
+	case err != nil:
+		o.r.logger.LogAttrs(o.ctx, slog.LevelDebug, "model server request failed", append(attrs, slog.Any("error", err))...)
+	case o.r.slowThreshold > 0 && duration > o.r.slowThreshold:
+		attrs = append(attrs, slog.Duration("threshold", o.r.slowThreshold))
+		if attr, ok := o.r.inputAttr(o.inputs); ok {
+			attrs = append(attrs, attr)
+		}
+		o.r.logger.LogAttrs(o.ctx, slog.LevelWarn, "slow model server request", attrs...)
+	default:
+		o.r.logger.LogAttrs(o.ctx, slog.LevelDebug, "model server request completed", attrs...)
+	}
+}
+
+// retryStatsHandler records and logs the retries of the RPCs made for an observed request. gRPC retries the
+// RPCs below the interceptors, but notifies the stats handlers of the beginning of every attempt.
+type retryStatsHandler struct{}
+
+var _ stats.Handler = retryStatsHandler{}
+
+// TagRPC implements stats.Handler.
+func (retryStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC implements stats.Handler.
+func (retryStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	begin, ok := s.(*stats.Begin)
+	if !ok {
+		return
+	}
+
+	obs, ok := ctx.Value(observationKey{}).(*observation)
+	if !ok {
+		return
+	}
+	attempt := obs.attempts.Add(1)
+	if attempt == 1 {
+		return
+	}
+
+	obs.r.metrics.ObserveRetry(obs.operation, obs.modelName, obs.modelVersion)
+	attrs := []slog.Attr{
+		slog.String("operation", obs.operation),
+		slog.String("model_name", obs.modelName),
+		slog.String("model_version", obs.modelVersion),
+		slog.Int("attempt", int(attempt)),
+		slog.Bool("transparent", begin.IsTransparentRetryAttempt),
+	}
+	if obs.requestID != "" {
+		attrs = append(attrs, slog.String("request_id", obs.requestID))
+	}
+	obs.r.logger.LogAttrs(ctx, slog.LevelWarn, "retrying model server request", attrs...)
+}
+
+// TagConn implements stats.Handler.
+func (retryStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn implements stats.Handler.
+func (retryStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
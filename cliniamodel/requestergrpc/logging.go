@@ -0,0 +1,90 @@
+package requestergrpc
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"google.golang.org/grpc/connectivity"
+)
+
+// watchConnectivity logs the state transitions of the connection to the model server until it is closed.
+func (r *requester) watchConnectivity() {
+	ctx := context.Background()
+	state := r.conn.GetState()
+	for state != connectivity.Shutdown {
+		if !r.conn.WaitForStateChange(ctx, state) {
+			return
+		}
+
+		newState := r.conn.GetState()
+		level := slog.LevelDebug
+		if newState == connectivity.TransientFailure {
+			level = slog.LevelWarn
+		}
+		r.logger.Log(ctx, level, "model server connection state changed",
+			slog.String("target", r.conn.Target()),
+			slog.String("from", state.String()),
+			slog.String("to", newState.String()),
+		)
+		state = newState
+	}
+}
+
+// readinessTracker logs the readiness transitions of the server and its models.
+type readinessTracker struct {
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	ready map[string]bool
+}
+
+func newReadinessTracker(logger *slog.Logger) *readinessTracker {
+	return &readinessTracker{
+		logger: logger,
+		ready:  map[string]bool{},
+	}
+}
+
+// record records the readiness of the given target and logs it if it changed since the last check.
+func (t *readinessTracker) record(ctx context.Context, target string, err error, attrs ...slog.Attr) {
+	ready := err == nil
+
+	t.mu.Lock()
+	prev, known := t.ready[target]
+	t.ready[target] = ready
+	t.mu.Unlock()
+
+	if known && prev == ready {
+		return
+	}
+
+	if ready {
+		t.logger.LogAttrs(ctx, slog.LevelInfo, "model server target is ready", attrs...)
+	} else {
+		t.logger.LogAttrs(ctx, slog.LevelWarn, "model server target is not ready", append(attrs, slog.Any("error", err))...)
+	}
+}
+
+// inputAttr returns the attribute logging the input texts of the request, if enabled.
+func (r *requester) inputAttr(inputs []common.Input) (slog.Attr, bool) {
+	if !r.logInputs {
+		return slog.Attr{}, false
+	}
+
+	attrs := make([]any, 0, len(inputs))
+	for _, input := range inputs {
+		texts := input.GetStringContents()
+		if r.redactInput != nil {
+			redacted := make([]string, len(texts))
+			for i, text := range texts {
+				redacted[i] = r.redactInput(text)
+			}
+			texts = redacted
+		}
+		attrs = append(attrs, slog.Any(input.Name, texts))
+	}
+
+	return slog.Group("inputs", attrs...), true
+}
@@ -0,0 +1,41 @@
+package requestergrpc
+
+import (
+	"context"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"google.golang.org/grpc/metadata"
+)
+
+// withOutgoingMetadata returns a context whose outgoing gRPC metadata carries the metadata of ctx (see
+// common.ContextWithMetadata) and the request ID, if any. ctx is returned as is if there is none.
+func withOutgoingMetadata(ctx context.Context, id string) context.Context {
+	extra := common.MetadataFromContext(ctx)
+	if id == "" && len(extra) == 0 {
+		return ctx
+	}
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	for key, value := range extra {
+		md.Set(key, value)
+	}
+	if id != "" {
+		md.Set(common.MetadataRequestID, id)
+	}
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// wrapRequestError wraps the error of a request with its ID, if known.
+func wrapRequestError(id string, err error) error {
+	if id == "" {
+		return err
+	}
+
+	return &common.RequestError{RequestID: id, Err: err}
+}
@@ -0,0 +1,206 @@
+package requestergrpc
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	requestergrpc "github.com/clinia/models-client-go/cliniamodel/requestergrpc/gen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Option configures the gRPC transport of the requester.
+type Option func(*options)
+
+type options struct {
+	dialOptions []grpc.DialOption
+
+	authorization         string
+	insecureAuthorization bool
+}
+
+// WithUnaryInterceptors adds interceptors to the unary RPCs made by the requester (e.g. Infer, Ready).
+// The interceptors are called in order, after the built-in ones.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, grpc.WithChainUnaryInterceptor(interceptors...))
+	}
+}
+
+// WithStreamInterceptors adds interceptors to the streaming RPCs made by the requester (e.g. Stream).
+// The interceptors are called in order, after the built-in ones.
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, grpc.WithChainStreamInterceptor(interceptors...))
+	}
+}
+
+// WithKeepalive configures the keepalive pings sent on the connection to the model server.
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, grpc.WithKeepaliveParams(params))
+	}
+}
+
+// WithMaxMessageSize sets the maximum size in bytes of the messages sent to and received from the
+// model server. A size of 0 keeps the gRPC default (unlimited to send, 4 MB to receive).
+func WithMaxMessageSize(maxSendBytes, maxRecvBytes int) Option {
+	return func(o *options) {
+		var callOpts []grpc.CallOption
+		if maxSendBytes > 0 {
+			callOpts = append(callOpts, grpc.MaxCallSendMsgSize(maxSendBytes))
+		}
+		if maxRecvBytes > 0 {
+			callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(maxRecvBytes))
+		}
+		o.dialOptions = append(o.dialOptions, grpc.WithDefaultCallOptions(callOpts...))
+	}
+}
+
+// WithGzipCompression compresses the requests sent to the model server with gzip. The compressed
+// responses of the model server are decompressed regardless of this option.
+func WithGzipCompression() Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+	}
+}
+
+// WithInitialWindowSize sets the initial flow control window sizes in bytes of the streams and of the
+// connection. Values below 64 KB are ignored by gRPC.
+func WithInitialWindowSize(streamBytes, connBytes int32) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions,
+			grpc.WithInitialWindowSize(streamBytes),
+			grpc.WithInitialConnWindowSize(connBytes),
+		)
+	}
+}
+
+// WithDialOptions appends arbitrary dial options to the ones built by the requester.
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, dialOptions...)
+	}
+}
+
+// WithTimeout bounds the duration of the unary RPCs made by the requester (e.g. Infer, Ready). A deadline
+// already set on the context of a call is kept if it is earlier. Streams are not bounded.
+func WithTimeout(timeout time.Duration) Option {
+	return WithUnaryInterceptors(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	})
+}
+
+// RetryPolicy configures the transparent retries of the unary RPCs failing with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of an RPC, including the first one. gRPC caps it to 5.
+	MaxAttempts int
+	// InitialBackoff is the maximum delay before the first retry. The delays are randomized.
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper bound of the delay between two attempts.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor by which the delay grows after each attempt. Defaults to 2.
+	BackoffMultiplier float64
+	// RetryableCodes are the gRPC status codes that are retried (e.g. "UNAVAILABLE"). Defaults to UNAVAILABLE.
+	RetryableCodes []string
+}
+
+// WithRetryPolicy retries the RPCs of the inference service with the given policy, through the retry
+// support of gRPC. Requests are only retried if no response was received from the model server. The retries
+// are recorded with the metrics recorder of the requester.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	multiplier := policy.BackoffMultiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	codes := policy.RetryableCodes
+	if len(codes) == 0 {
+		codes = []string{"UNAVAILABLE"}
+	}
+
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type name struct {
+		Service string `json:"service"`
+	}
+	type methodConfig struct {
+		Name        []name      `json:"name"`
+		RetryPolicy retryPolicy `json:"retryPolicy"`
+	}
+	serviceConfig, _ := json.Marshal(struct {
+		MethodConfig []methodConfig `json:"methodConfig"`
+	}{
+		MethodConfig: []methodConfig{{
+			Name: []name{{Service: requestergrpc.GRPCInferenceService_ServiceDesc.ServiceName}},
+			RetryPolicy: retryPolicy{
+				MaxAttempts:          policy.MaxAttempts,
+				InitialBackoff:       durationSeconds(policy.InitialBackoff),
+				MaxBackoff:           durationSeconds(policy.MaxBackoff),
+				BackoffMultiplier:    multiplier,
+				RetryableStatusCodes: codes,
+			},
+		}},
+	})
+
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, grpc.WithDefaultServiceConfig(string(serviceConfig)))
+	}
+}
+
+// durationSeconds formats the duration in the "1.5s" format of the gRPC service config.
+func durationSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// WithAPIKey sends `Authorization: Api-Key <key>` in the metadata of every RPC.
+func WithAPIKey(key string) Option {
+	return WithAuthorization("Api-Key " + key)
+}
+
+// WithBearer sends `Authorization: Bearer <token>` in the metadata of every RPC.
+func WithBearer(token string) Option {
+	return WithAuthorization("Bearer " + token)
+}
+
+// WithAuthorization sends the given authorization in the metadata of every RPC. The credentials are only
+// sent to hosts reached with TLS, unless WithInsecureAuthorization is given.
+func WithAuthorization(authorization string) Option {
+	return func(o *options) {
+		o.authorization = authorization
+	}
+}
+
+// WithInsecureAuthorization allows the credentials of WithAuthorization to be sent in plaintext to hosts
+// reached without transport security (e.g. a model server on the local network). For development only.
+func WithInsecureAuthorization() Option {
+	return func(o *options) {
+		o.insecureAuthorization = true
+	}
+}
+
+// authorizationCredentials are per-RPC credentials sending a static authorization.
+type authorizationCredentials struct {
+	authorization string
+	insecure      bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c authorizationCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": c.authorization}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (c authorizationCredentials) RequireTransportSecurity() bool {
+	return !c.insecure
+}
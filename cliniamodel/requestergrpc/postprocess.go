@@ -1,52 +1,79 @@
 package requestergrpc
 
 import (
-	"encoding/binary"
-	"errors"
-	"math"
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	requestergrpc "github.com/clinia/models-client-go/cliniamodel/requestergrpc/gen"
 )
 
-// decodeFloat32 decodes a byte array into a float32 array.
-func decodeFloat32(encodedTensor []byte) ([]float32, error) {
-	if len(encodedTensor)%4 != 0 {
-		return nil, errors.New("encoded tensor length must be a multiple of 4")
+// toTensorMetadata converts the tensor metadata reported by Triton to common.TensorMetadata.
+func toTensorMetadata(tensors []*requestergrpc.ModelMetadataResponse_TensorMetadata) []common.TensorMetadata {
+	metadata := make([]common.TensorMetadata, len(tensors))
+	for i, tensor := range tensors {
+		metadata[i] = common.TensorMetadata{
+			Name:     tensor.GetName(),
+			Datatype: datatype.Datatype(tensor.GetDatatype()),
+			Shape:    tensor.GetShape(),
+		}
 	}
 
-	var floats []float32
-	offset := 0
+	return metadata
+}
 
-	for offset < len(encodedTensor) {
-		if offset+4 > len(encodedTensor) {
-			return nil, errors.New("unexpected end of data")
-		}
+// matchOutputs checks that the response outputs are exactly the requested outputs, regardless of their order.
+func matchOutputs(outputKeys []string, outputs []*requestergrpc.ModelInferResponse_InferOutputTensor) error {
+	requested := make(map[string]struct{}, len(outputKeys))
+	for _, outputKey := range outputKeys {
+		requested[outputKey] = struct{}{}
+	}
 
-		// Read 4 bytes for float32
-		bf32 := encodedTensor[offset : offset+4]
-		offset += 4
+	received := make(map[string]struct{}, len(outputs))
+	var unexpected []string
+	for _, output := range outputs {
+		received[output.Name] = struct{}{}
+		if _, ok := requested[output.Name]; !ok {
+			unexpected = append(unexpected, output.Name)
+		}
+	}
 
-		// Convert bytes to float32
-		bits := binary.LittleEndian.Uint32(bf32)
-		f := math.Float32frombits(bits)
+	var missing []string
+	for _, outputKey := range outputKeys {
+		if _, ok := received[outputKey]; !ok {
+			missing = append(missing, outputKey)
+		}
+	}
 
-		floats = append(floats, f)
+	if len(missing) > 0 || len(unexpected) > 0 {
+		return &common.OutputMismatchError{
+			Missing:    missing,
+			Unexpected: unexpected,
+		}
 	}
 
-	return floats, nil
+	return nil
 }
 
-// decodeString decodes a byte array into a string array.
-func decodeString(encodedTensor []byte) ([]string, error) {
-	var strs []string
-	offset := 0
-
-	for offset < len(encodedTensor) {
-		length := binary.LittleEndian.Uint32(encodedTensor[offset : offset+4])
-		offset += 4
+// fromInferParameters converts the Triton inference parameters of a response to common.Parameters.
+func fromInferParameters(inferParams map[string]*requestergrpc.InferParameter) common.Parameters {
+	if len(inferParams) == 0 {
+		return nil
+	}
 
-		sb := string(encodedTensor[offset : offset+int(length)])
-		offset += int(length)
-		strs = append(strs, sb)
+	params := make(common.Parameters, len(inferParams))
+	for key, param := range inferParams {
+		switch v := param.GetParameterChoice().(type) {
+		case *requestergrpc.InferParameter_BoolParam:
+			params[key] = v.BoolParam
+		case *requestergrpc.InferParameter_Int64Param:
+			params[key] = v.Int64Param
+		case *requestergrpc.InferParameter_Uint64Param:
+			params[key] = v.Uint64Param
+		case *requestergrpc.InferParameter_DoubleParam:
+			params[key] = v.DoubleParam
+		case *requestergrpc.InferParameter_StringParam:
+			params[key] = v.StringParam
+		}
 	}
 
-	return strs, nil
+	return params
 }
@@ -1,66 +1,70 @@
 package requestergrpc
 
 import (
-	"bytes"
-	"encoding/binary"
-	"errors"
 	"fmt"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	requestergrpc "github.com/clinia/models-client-go/cliniamodel/requestergrpc/gen"
 )
 
+// preprocessString serializes the texts into a BYTES tensor of shape [len(texts), 1].
 func preprocessString(texts []string) ([]byte, []int64, error) {
-	encodedText := encodeString(texts)
-	shape := []int64{int64(len(encodedText)), 1}
+	shape := []int64{int64(len(texts)), 1}
 
-	inputs, err := serializeByteTensor(encodedText)
+	rawContents, err := encodeStrings(texts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return inputs, shape, nil
-}
-
-// encodeString converts a slice of string into a 2D byte tensor.
-func encodeString(texts []string) [][]byte {
-	// Convert texts to []byte slices
-	encodedData := make([][]byte, len(texts))
-	for i, text := range texts {
-		encodedData[i] = []byte(text)
-	}
-	return encodedData
+	return rawContents, shape, nil
 }
 
-// serializeByteTensor serializes a 2D byte tensor into a flat byte array.
-func serializeByteTensor(inputTensor [][]byte) ([]byte, error) {
-	if len(inputTensor) == 0 {
-		return make([]byte, 0), nil
+// toInferParameters converts the request parameters to Triton inference parameters.
+func toInferParameters(params common.Parameters) (map[string]*requestergrpc.InferParameter, error) {
+	if len(params) == 0 {
+		return nil, nil
 	}
 
-	var flattenedBytesBuffer bytes.Buffer
-
-	for _, tensor := range inputTensor {
-		if tensor == nil {
-			return nil, errors.New("cannot serialize bytes tensor: got nil tensor")
+	inferParams := make(map[string]*requestergrpc.InferParameter, len(params))
+	for key, value := range params {
+		param := &requestergrpc.InferParameter{}
+		switch v := value.(type) {
+		case bool:
+			param.ParameterChoice = &requestergrpc.InferParameter_BoolParam{BoolParam: v}
+		case int:
+			param.ParameterChoice = &requestergrpc.InferParameter_Int64Param{Int64Param: int64(v)}
+		case int64:
+			param.ParameterChoice = &requestergrpc.InferParameter_Int64Param{Int64Param: v}
+		case uint64:
+			param.ParameterChoice = &requestergrpc.InferParameter_Uint64Param{Uint64Param: v}
+		case float64:
+			param.ParameterChoice = &requestergrpc.InferParameter_DoubleParam{DoubleParam: v}
+		case string:
+			param.ParameterChoice = &requestergrpc.InferParameter_StringParam{StringParam: v}
+		default:
+			return nil, fmt.Errorf("unsupported type %T for parameter %q", value, key)
 		}
+		inferParams[key] = param
+	}
 
-		// Prepend the byte length as 4-byte little endian integer
-		// #nosec G115
-		length := uint32(len(tensor))
-		if err := binary.Write(&flattenedBytesBuffer, binary.LittleEndian, length); err != nil {
-			return nil, err
-		}
+	return inferParams, nil
+}
 
-		// Write the actual bytes
-		if _, err := flattenedBytesBuffer.Write(tensor); err != nil {
-			return nil, err
-		}
+// inputBatchSize returns the batch size of the inputs, which is the number of elements of the first input.
+func inputBatchSize(inputs []common.Input) int {
+	if len(inputs) == 0 {
+		return 0
 	}
 
-	return flattenedBytesBuffer.Bytes(), nil
+	return len(inputs[0].GetStringContents())
 }
 
-// formatModelNameAndVersion formats the model name and version for the request.
-// The model version is always set to 1 because all models deployed within the same Triton
-// server instance -- when stored in different model repositories -- must have unique names.
-func formatModelNameAndVersion(modelName string, modelVersion string) (string, string) {
-	return fmt.Sprintf("%s:%s", modelName, modelVersion), "1"
+// payloadSize returns the size in bytes of the raw input contents of the request.
+func payloadSize(req *requestergrpc.ModelInferRequest) int {
+	size := 0
+	for _, rawInput := range req.RawInputContents {
+		size += len(rawInput)
+	}
+
+	return size
 }
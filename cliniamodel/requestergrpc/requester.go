@@ -2,44 +2,208 @@ package requestergrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/clinia/models-client-go/cliniamodel/common"
 	"github.com/clinia/models-client-go/cliniamodel/datatype"
 	requestergrpc "github.com/clinia/models-client-go/cliniamodel/requestergrpc/gen"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 type requester struct {
 	conn *grpc.ClientConn
 
+	naming  common.ModelNaming
+	tracer  trace.Tracer
+	metrics common.MetricsRecorder
+
+	maxRequestBytes int
+	maxElementBytes int
+
+	logger        *slog.Logger
+	slowThreshold time.Duration
+	logInputs     bool
+	redactInput   func(text string) string
+	readiness     *readinessTracker
+
 	inferenceServiceClient requestergrpc.GRPCInferenceServiceClient
 }
 
 var _ common.Requester = (*requester)(nil)
 
-func NewRequester(ctx context.Context, cfg common.RequesterConfig) (common.Requester, error) {
-	opts := []grpc.DialOption{}
+// NewRequester creates a requester communicating with the model server over gRPC.
+func NewRequester(ctx context.Context, cfg common.RequesterConfig, opts ...Option) (common.Requester, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-	// Set insecure credentials if the host is HTTP
-	if cfg.Host.Scheme == common.HTTP {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	propagator := common.Propagator(cfg.Propagator)
+	dialOpts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unaryTracingInterceptor(propagator)),
+		grpc.WithChainStreamInterceptor(streamTracingInterceptor(propagator)),
+		grpc.WithStatsHandler(retryStatsHandler{}),
 	}
 
-	conn, err := grpc.NewClient(cfg.Host.Host(), opts...)
+	if err := cfg.Host.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid host: %w", err)
+	}
+
+	// Set TLS credentials if the host is HTTPS, insecure credentials otherwise
+	if cfg.Host.Secure() {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(cfg.TLSConfig)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	if o.authorization != "" {
+		if !cfg.Host.Secure() && !o.insecureAuthorization {
+			return nil, errors.New("authorization requires a host reached with TLS, see WithInsecureAuthorization")
+		}
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(authorizationCredentials{
+			authorization: o.authorization,
+			insecure:      o.insecureAuthorization,
+		}))
+	}
+	dialOpts = append(dialOpts, o.dialOptions...)
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	conn, err := grpc.NewClient(cfg.Host.Target(), dialOpts...)
 	if err != nil {
 		return nil, err
 	}
+	logger.DebugContext(ctx, "created model server connection", slog.String("target", conn.Target()))
+
+	naming := cfg.ModelNaming
+	if naming == nil {
+		naming = common.ComposedModelNaming{}
+	}
+
+	maxElementBytes := cfg.MaxElementBytes
+	if maxElementBytes <= 0 {
+		maxElementBytes = common.DefaultMaxElementBytes
+	}
+
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = common.NoopMetricsRecorder{}
+	}
 
-	return &requester{
+	r := &requester{
 		conn:                   conn,
+		naming:                 naming,
+		maxRequestBytes:        cfg.MaxRequestBytes,
+		maxElementBytes:        maxElementBytes,
+		tracer:                 common.Tracer(cfg.TracerProvider),
+		metrics:                metrics,
+		logger:                 logger,
+		slowThreshold:          cfg.SlowRequestThreshold,
+		logInputs:              cfg.LogInputs,
+		redactInput:            cfg.RedactInput,
+		readiness:              newReadinessTracker(logger),
 		inferenceServiceClient: requestergrpc.NewGRPCInferenceServiceClient(conn),
-	}, nil
+	}
+
+	if cfg.Logger != nil {
+		go r.watchConnectivity()
+	}
+
+	return r, nil
 }
 
 // Infer implements common.Requester.
 func (r *requester) Infer(ctx context.Context, req common.InferRequest) (*common.InferResponse, error) {
+	// Requests made without an ID use the one carried by the context, if any.
+	if req.ID == "" {
+		req.ID, _ = common.RequestIDFromContext(ctx)
+	}
+
+	res, err := r.inferSplit(withOutgoingMetadata(ctx, req.ID), req)
+	if err != nil {
+		return nil, wrapRequestError(req.ID, err)
+	}
+
+	return res, nil
+}
+
+// inferSplit sends the request, split in sub-requests if it exceeds the request size budget.
+func (r *requester) inferSplit(ctx context.Context, req common.InferRequest) (*common.InferResponse, error) {
+	if r.maxRequestBytes <= 0 {
+		return r.inferOne(ctx, req)
+	}
+
+	reqs, err := splitInferRequest(req, r.maxRequestBytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(reqs) == 1 {
+		return r.inferOne(ctx, req)
+	}
+
+	// The sub-requests are sent one after the other to avoid flooding the model server.
+	responses := make([]*common.InferResponse, len(reqs))
+	for i, subReq := range reqs {
+		responses[i], err = r.inferOne(ctx, subReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mergeInferResponses(responses)
+}
+
+// inferOne sends a single inference request to the model server.
+func (r *requester) inferOne(ctx context.Context, req common.InferRequest) (*common.InferResponse, error) {
+	ctx, obs := r.observe(ctx, common.OperationInfer, "ModelInfer", req.ModelName, req.ModelVersion, inputBatchSize(req.Inputs))
+	obs.requestID = req.ID
+	obs.inputs = req.Inputs
+	res, err := r.infer(ctx, obs, req)
+	obs.end(err)
+
+	return res, err
+}
+
+func (r *requester) infer(ctx context.Context, obs *observation, req common.InferRequest) (*common.InferResponse, error) {
+	inferReq, err := r.newModelInferRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	obs.setPayloadBytes(payloadSize(inferReq))
+
+	res, err := r.inferenceServiceClient.ModelInfer(ctx, inferReq)
+	if err != nil {
+		return nil, err
+	}
+
+	inferRes, err := r.parseModelInferResponse(req, res)
+	if err != nil {
+		attrs := []slog.Attr{
+			slog.String("model_name", req.ModelName),
+			slog.String("model_version", req.ModelVersion),
+			slog.String("request_id", req.ID),
+			slog.Any("error", err),
+		}
+		if attr, ok := r.inputAttr(req.Inputs); ok {
+			attrs = append(attrs, attr)
+		}
+		r.logger.LogAttrs(ctx, slog.LevelWarn, "failed to decode inference response", attrs...)
+		return nil, err
+	}
+
+	return inferRes, nil
+}
+
+// newModelInferRequest prepares the Triton inference request for the given request.
+func (r *requester) newModelInferRequest(req common.InferRequest) (*requestergrpc.ModelInferRequest, error) {
 	// Prepare input tensors
 	grpcInputs := make([]*requestergrpc.ModelInferRequest_InferInputTensor, len(req.Inputs))
 	rawInputs := make([][]byte, len(req.Inputs))
@@ -52,7 +216,7 @@ func (r *requester) Infer(ctx context.Context, req common.InferRequest) (*common
 
 		rawInputContents, shape, err := preprocessString(input.GetStringContents())
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("input %s: %w", input.Name, err)
 		}
 
 		grpcInputs[i] = &requestergrpc.ModelInferRequest_InferInputTensor{
@@ -72,28 +236,39 @@ func (r *requester) Infer(ctx context.Context, req common.InferRequest) (*common
 		}
 	}
 
+	// Prepare parameters
+	grpcParameters, err := toInferParameters(req.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
 	// Format model name and version
-	formattedModelName, formattedModelVersion := formatModelNameAndVersion(req.ModelName, req.ModelVersion)
-	res, err := r.inferenceServiceClient.ModelInfer(ctx, &requestergrpc.ModelInferRequest{
+	formattedModelName, formattedModelVersion := r.naming.Format(req.ModelName, req.ModelVersion)
+	return &requestergrpc.ModelInferRequest{
 		Id:               req.ID,
 		ModelName:        formattedModelName,
 		ModelVersion:     formattedModelVersion,
+		Parameters:       grpcParameters,
 		Inputs:           grpcInputs,
 		Outputs:          grpcOutputs,
 		RawInputContents: rawInputs,
-	})
-
-	if err != nil {
-		return nil, err
-	}
+	}, nil
+}
 
+// parseModelInferResponse validates the Triton inference response against the request and decodes its outputs.
+func (r *requester) parseModelInferResponse(req common.InferRequest, res *requestergrpc.ModelInferResponse) (*common.InferResponse, error) {
 	if res.Id != req.ID {
 		return nil, fmt.Errorf("unexpected response ID: %s", res.Id)
 	}
 
-	// Check if the number of output keys matches the number of outputs
-	if len(res.RawOutputContents) != len(req.OutputKeys) {
-		return nil, fmt.Errorf("expected %d output keys, got %d", len(req.OutputKeys), len(res.RawOutputContents))
+	// Check that every requested output is present, and only those.
+	if err := matchOutputs(req.OutputKeys, res.Outputs); err != nil {
+		return nil, err
+	}
+
+	// Check if the number of raw outputs matches the number of outputs
+	if len(res.RawOutputContents) != len(res.Outputs) {
+		return nil, fmt.Errorf("expected %d raw outputs, got %d", len(res.Outputs), len(res.RawOutputContents))
 	}
 
 	// Prepare output tensors
@@ -101,13 +276,15 @@ func (r *requester) Infer(ctx context.Context, req common.InferRequest) (*common
 	for i, rawOutput := range res.RawOutputContents {
 		resOutput := res.Outputs[i]
 
-		// For now, we only support FP32 & bytes/string datatype
 		switch resOutput.Datatype {
 		case string(datatype.Fp32):
-			fp32Contents, err := decodeFloat32(rawOutput)
+			fp32Contents, err := decodeFloat32(resOutput.Name, rawOutput)
 			if err != nil {
 				return nil, err
 			}
+			if err := checkElementCount(resOutput.Name, resOutput.Shape, len(fp32Contents)); err != nil {
+				return nil, err
+			}
 
 			outputs[i] = common.Output{
 				Name:     resOutput.Name,
@@ -117,11 +294,48 @@ func (r *requester) Infer(ctx context.Context, req common.InferRequest) (*common
 					Fp32Contents: fp32Contents,
 				},
 			}
+		case string(datatype.Int32):
+			int32Contents, err := decodeInt32(resOutput.Name, rawOutput)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkElementCount(resOutput.Name, resOutput.Shape, len(int32Contents)); err != nil {
+				return nil, err
+			}
+
+			outputs[i] = common.Output{
+				Name:     resOutput.Name,
+				Shape:    resOutput.Shape,
+				Datatype: datatype.Int32,
+				Content: common.Content{
+					Int32Contents: int32Contents,
+				},
+			}
+		case string(datatype.Bool):
+			boolContents, err := decodeBool(resOutput.Name, rawOutput)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkElementCount(resOutput.Name, resOutput.Shape, len(boolContents)); err != nil {
+				return nil, err
+			}
+
+			outputs[i] = common.Output{
+				Name:     resOutput.Name,
+				Shape:    resOutput.Shape,
+				Datatype: datatype.Bool,
+				Content: common.Content{
+					BoolContents: boolContents,
+				},
+			}
 		case string(datatype.Bytes):
-			stringContents, err := decodeString(rawOutput)
+			stringContents, err := decodeString(resOutput.Name, rawOutput, r.maxElementBytes)
 			if err != nil {
 				return nil, err
 			}
+			if err := checkElementCount(resOutput.Name, resOutput.Shape, len(stringContents)); err != nil {
+				return nil, err
+			}
 
 			outputs[i] = common.Output{
 				Name:     resOutput.Name,
@@ -137,15 +351,26 @@ func (r *requester) Infer(ctx context.Context, req common.InferRequest) (*common
 	}
 
 	return &common.InferResponse{
-		ID:      res.Id,
-		Outputs: outputs,
+		ID:         res.Id,
+		Outputs:    outputs,
+		Parameters: fromInferParameters(res.Parameters),
 	}, nil
 }
 
 // Ready implements common.Requester.
 func (r *requester) Ready(ctx context.Context, modelName string, modelVersion string) error {
+	err := r.ready(ctx, modelName, modelVersion)
+	r.readiness.record(ctx, modelName+":"+modelVersion, err,
+		slog.String("model_name", modelName),
+		slog.String("model_version", modelVersion),
+	)
+
+	return err
+}
+
+func (r *requester) ready(ctx context.Context, modelName string, modelVersion string) error {
 	// Format model name and version
-	formattedModelName, formattedModelVersion := formatModelNameAndVersion(modelName, modelVersion)
+	formattedModelName, formattedModelVersion := r.naming.Format(modelName, modelVersion)
 	res, err := r.inferenceServiceClient.ModelReady(ctx, &requestergrpc.ModelReadyRequest{
 		Name:    formattedModelName,
 		Version: formattedModelVersion,
@@ -161,8 +386,57 @@ func (r *requester) Ready(ctx context.Context, modelName string, modelVersion st
 	return nil
 }
 
+// Metadata implements common.Requester.
+func (r *requester) Metadata(ctx context.Context, modelName string, modelVersion string) (*common.ModelMetadata, error) {
+	// Format model name and version
+	formattedModelName, formattedModelVersion := r.naming.Format(modelName, modelVersion)
+	res, err := r.inferenceServiceClient.ModelMetadata(ctx, &requestergrpc.ModelMetadataRequest{
+		Name:    formattedModelName,
+		Version: formattedModelVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.ModelMetadata{
+		ModelName:    modelName,
+		ModelVersion: modelVersion,
+		Platform:     res.Platform,
+		Inputs:       toTensorMetadata(res.Inputs),
+		Outputs:      toTensorMetadata(res.Outputs),
+	}, nil
+}
+
+// RepositoryIndex implements common.Requester.
+func (r *requester) RepositoryIndex(ctx context.Context) ([]common.ModelIndex, error) {
+	res, err := r.inferenceServiceClient.RepositoryIndex(ctx, &requestergrpc.RepositoryIndexRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]common.ModelIndex, len(res.Models))
+	for i, model := range res.Models {
+		modelName, modelVersion := r.naming.Parse(model.Name, model.Version)
+		models[i] = common.ModelIndex{
+			ModelName:    modelName,
+			ModelVersion: modelVersion,
+			State:        model.State,
+			Reason:       model.Reason,
+		}
+	}
+
+	return models, nil
+}
+
 // Health implements common.Requester.
 func (r *requester) Health(ctx context.Context) error {
+	err := r.health(ctx)
+	r.readiness.record(ctx, "", err, slog.String("target", r.conn.Target()))
+
+	return err
+}
+
+func (r *requester) health(ctx context.Context) error {
 	res, err := r.inferenceServiceClient.ServerReady(ctx, &requestergrpc.ServerReadyRequest{})
 	if err != nil {
 		return err
@@ -175,11 +449,6 @@ func (r *requester) Health(ctx context.Context) error {
 	return nil
 }
 
-// Stream implements common.Requester.
-func (r *requester) Stream(ctx context.Context, modelName string, modelVersion string, inputs []common.Input) (chan<- string, error) {
-	panic("unimplemented")
-}
-
 func (r *requester) Close() error {
 	return r.conn.Close()
 }
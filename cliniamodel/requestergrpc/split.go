@@ -0,0 +1,210 @@
+package requestergrpc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/clinia/models-client-go/cliniamodel/datatype"
+)
+
+// elementSize returns the serialized size of the element i of a BYTES input, including its length prefix.
+func elementSize(input common.Input, i int) int {
+	return 4 + len(input.GetStringContents()[i])
+}
+
+// splitInferRequest splits the request along the batch dimension so that the serialized inputs of each
+// sub-request fit in maxBytes. The request is returned as is if it already fits.
+func splitInferRequest(req common.InferRequest, maxBytes int) ([]common.InferRequest, error) {
+	batchSize := inputBatchSize(req.Inputs)
+	total := 0
+	for _, input := range req.Inputs {
+		if len(input.GetStringContents()) != batchSize {
+			// Only inputs sharing the batch dimension can be split. Let the server validate the others.
+			return []common.InferRequest{req}, nil
+		}
+		for i := range batchSize {
+			total += elementSize(input, i)
+		}
+	}
+	if total <= maxBytes {
+		return []common.InferRequest{req}, nil
+	}
+
+	// Splitting a sequence request would break the start and end markers of the sequence.
+	if _, ok := req.Parameters[common.ParameterSequenceID]; ok {
+		return nil, fmt.Errorf("sequence request of %d bytes exceeds the request size budget of %d bytes", total, maxBytes)
+	}
+
+	var reqs []common.InferRequest
+	start, size := 0, 0
+	for i := range batchSize {
+		elemSize := 0
+		for _, input := range req.Inputs {
+			elemSize += elementSize(input, i)
+		}
+		if elemSize > maxBytes {
+			return nil, &common.PayloadTooLargeError{Index: i, Size: elemSize, Limit: maxBytes}
+		}
+
+		if size+elemSize > maxBytes {
+			reqs = append(reqs, sliceInferRequest(req, start, i))
+			start, size = i, 0
+		}
+		size += elemSize
+	}
+	reqs = append(reqs, sliceInferRequest(req, start, batchSize))
+
+	return reqs, nil
+}
+
+// sliceInferRequest returns a copy of the request restricted to the elements [start, end) of the batch.
+func sliceInferRequest(req common.InferRequest, start, end int) common.InferRequest {
+	inputs := make([]common.Input, len(req.Inputs))
+	for i, input := range req.Inputs {
+		inputs[i] = common.Input{
+			Name:     input.Name,
+			Datatype: input.Datatype,
+			Content: common.Content{
+				StringContents: input.GetStringContents()[start:end],
+			},
+		}
+		if len(input.Shape) > 0 {
+			inputs[i].Shape = append([]int64{int64(end - start)}, input.Shape[1:]...)
+		}
+	}
+
+	req.Inputs = inputs
+	return req
+}
+
+// mergeInferResponses concatenates the outputs of the responses to the sub-requests of a split request
+// along the batch dimension. Outputs whose other dimensions vary across the responses (e.g. the number of
+// chunks of each text) are padded to the largest dimensions, BYTES outputs with common.OutputPadValue and the
+// other outputs with zero values.
+func mergeInferResponses(responses []*common.InferResponse) (*common.InferResponse, error) {
+	merged := &common.InferResponse{
+		ID:         responses[0].ID,
+		Parameters: responses[0].Parameters,
+		Outputs:    make([]common.Output, len(responses[0].Outputs)),
+	}
+
+	for i, output := range responses[0].Outputs {
+		resOutputs := make([]*common.Output, len(responses))
+		shape := slices.Clone(output.Shape)
+		for j, res := range responses {
+			resOutput, err := res.Output(output.Name)
+			if err != nil {
+				return nil, err
+			}
+			if len(resOutput.Shape) == 0 {
+				return nil, fmt.Errorf("cannot merge output %s without a batch dimension", output.Name)
+			}
+			if resOutput.Datatype != output.Datatype || len(resOutput.Shape) != len(output.Shape) {
+				return nil, fmt.Errorf("cannot merge output %s: datatypes or ranks differ across split requests", output.Name)
+			}
+
+			// The merged output has the largest dimensions of the responses.
+			for d := 1; d < len(shape); d++ {
+				shape[d] = max(shape[d], resOutput.Shape[d])
+			}
+			resOutputs[j] = resOutput
+		}
+
+		mergedOutput := common.Output{
+			Name:     output.Name,
+			Datatype: output.Datatype,
+			Shape:    append([]int64{0}, shape[1:]...),
+		}
+		for _, resOutput := range resOutputs {
+			target := append([]int64{resOutput.Shape[0]}, shape[1:]...)
+			content, err := padContent(resOutput, target)
+			if err != nil {
+				return nil, err
+			}
+
+			mergedOutput.Shape[0] += resOutput.Shape[0]
+			mergedOutput.Content.BoolContents = append(mergedOutput.Content.BoolContents, content.BoolContents...)
+			mergedOutput.Content.Int32Contents = append(mergedOutput.Content.Int32Contents, content.Int32Contents...)
+			mergedOutput.Content.Fp32Contents = append(mergedOutput.Content.Fp32Contents, content.Fp32Contents...)
+			mergedOutput.Content.StringContents = append(mergedOutput.Content.StringContents, content.StringContents...)
+		}
+		merged.Outputs[i] = mergedOutput
+	}
+
+	return merged, nil
+}
+
+// padContent pads the content of the output to the target shape, of the same rank.
+func padContent(output *common.Output, target []int64) (common.Content, error) {
+	if slices.Equal(output.Shape, target) {
+		return output.Content, nil
+	}
+
+	var err error
+	content := common.Content{}
+	switch output.Datatype {
+	case datatype.Bool:
+		content.BoolContents, err = padTensor(output.Content.BoolContents, output.Shape, target, false)
+	case datatype.Int32:
+		content.Int32Contents, err = padTensor(output.Content.Int32Contents, output.Shape, target, 0)
+	case datatype.Fp32:
+		content.Fp32Contents, err = padTensor(output.Content.Fp32Contents, output.Shape, target, 0)
+	case datatype.Bytes:
+		content.StringContents, err = padTensor(output.Content.StringContents, output.Shape, target, common.OutputPadValue)
+	default:
+		err = fmt.Errorf("unsupported output datatype: %v", output.Datatype)
+	}
+	if err != nil {
+		return common.Content{}, fmt.Errorf("cannot merge output %s: %w", output.Name, err)
+	}
+
+	return content, nil
+}
+
+// padTensor pads the row-major tensor of the given shape to the target shape, of the same rank and with
+// dimensions at least as large, filling the added elements with pad.
+func padTensor[T any](tensor []T, shape, target []int64, pad T) ([]T, error) {
+	if int64(len(tensor)) != product(shape) {
+		return nil, errors.New("element count does not match the shape")
+	}
+
+	padded := make([]T, product(target))
+	for i := range padded {
+		padded[i] = pad
+	}
+
+	// Copy the innermost rows of the tensor one by one, at their position in the padded tensor.
+	rank := len(shape)
+	rowSize := shape[rank-1]
+	index := make([]int64, rank-1)
+	for row := range product(shape[:rank-1]) {
+		offset := int64(0)
+		for d, i := range index {
+			offset = offset*target[d] + i
+		}
+		copy(padded[offset*target[rank-1]:], tensor[row*rowSize:(row+1)*rowSize])
+
+		// Move to the next row.
+		for d := rank - 2; d >= 0; d-- {
+			index[d]++
+			if index[d] < shape[d] {
+				break
+			}
+			index[d] = 0
+		}
+	}
+
+	return padded, nil
+}
+
+// product returns the number of elements of a tensor of the given shape.
+func product(shape []int64) int64 {
+	n := int64(1)
+	for _, dim := range shape {
+		n *= dim
+	}
+
+	return n
+}
@@ -0,0 +1,236 @@
+package requestergrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	"github.com/google/go-cmp/cmp"
+)
+
+// textInput returns a BYTES input of shape [len(texts), 1].
+func textInput(name string, texts ...string) common.Input {
+	return common.Input{
+		Name:     name,
+		Shape:    []int64{int64(len(texts)), 1},
+		Datatype: datatype.Bytes,
+		Content:  common.Content{StringContents: texts},
+	}
+}
+
+func TestSplitInferRequest(t *testing.T) {
+	// Every text is serialized in 4 bytes of length prefix and 4 bytes of content.
+	texts := []string{"aaaa", "bbbb", "cccc", "dddd", "eeee"}
+
+	tests := []struct {
+		name     string
+		req      common.InferRequest
+		maxBytes int
+		expected [][]string
+		err      error
+	}{
+		{
+			name:     "fits",
+			req:      common.InferRequest{Inputs: []common.Input{textInput("text", texts...)}},
+			maxBytes: 40,
+			expected: [][]string{texts},
+		},
+		{
+			name:     "split at the budget",
+			req:      common.InferRequest{Inputs: []common.Input{textInput("text", texts...)}},
+			maxBytes: 16,
+			expected: [][]string{{"aaaa", "bbbb"}, {"cccc", "dddd"}, {"eeee"}},
+		},
+		{
+			name:     "split one byte below the budget",
+			req:      common.InferRequest{Inputs: []common.Input{textInput("text", texts...)}},
+			maxBytes: 15,
+			expected: [][]string{{"aaaa"}, {"bbbb"}, {"cccc"}, {"dddd"}, {"eeee"}},
+		},
+		{
+			name:     "budget one byte below the total",
+			req:      common.InferRequest{Inputs: []common.Input{textInput("text", texts...)}},
+			maxBytes: 39,
+			expected: [][]string{{"aaaa", "bbbb", "cccc", "dddd"}, {"eeee"}},
+		},
+		{
+			name:     "oversize element",
+			req:      common.InferRequest{Inputs: []common.Input{textInput("text", "aaaa", "bbbbbbbbbbbb", "cccc")}},
+			maxBytes: 12,
+			err:      &common.PayloadTooLargeError{Index: 1, Size: 16, Limit: 12},
+		},
+		{
+			name: "sequence request",
+			req: common.InferRequest{
+				Inputs:     []common.Input{textInput("text", texts...)},
+				Parameters: common.Parameters{common.ParameterSequenceID: int64(42)},
+			},
+			maxBytes: 16,
+			err:      errors.New("sequence request of 40 bytes exceeds the request size budget of 16 bytes"),
+		},
+		{
+			name: "sequence request within the budget",
+			req: common.InferRequest{
+				Inputs:     []common.Input{textInput("text", texts...)},
+				Parameters: common.Parameters{common.ParameterSequenceID: int64(42)},
+			},
+			maxBytes: 40,
+			expected: [][]string{texts},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, err := splitInferRequest(tt.req, tt.maxBytes)
+			if tt.err != nil {
+				if err == nil || err.Error() != tt.err.Error() {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				var tooLarge *common.PayloadTooLargeError
+				if errors.As(tt.err, &tooLarge) && !errors.As(err, &tooLarge) {
+					t.Fatalf("expected a PayloadTooLargeError, got %T", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([][]string, len(reqs))
+			for i, req := range reqs {
+				got[i] = req.Inputs[0].GetStringContents()
+				if shape := req.Inputs[0].Shape; shape[0] != int64(len(got[i])) || shape[1] != 1 {
+					t.Errorf("sub-request %d: unexpected shape %v for %d elements", i, shape, len(got[i]))
+				}
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("unexpected sub-requests (-expected +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitInferRequestInputs(t *testing.T) {
+	// The elements of all the inputs count towards the budget, and the inputs are split together.
+	req := common.InferRequest{
+		Inputs: []common.Input{
+			textInput("query", "q1", "q2", "q3"),
+			textInput("text", "t1", "t2", "t3"),
+		},
+	}
+
+	reqs, err := splitInferRequest(req, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([][][]string, len(reqs))
+	for i, req := range reqs {
+		for _, input := range req.Inputs {
+			got[i] = append(got[i], input.GetStringContents())
+		}
+	}
+	expected := [][][]string{
+		{{"q1", "q2"}, {"t1", "t2"}},
+		{{"q3"}, {"t3"}},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected sub-requests (-expected +got):\n%s", diff)
+	}
+}
+
+func TestMergeInferResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []common.Output
+		expected  common.Output
+		err       string
+	}{
+		{
+			name: "BYTES of uneven length",
+			responses: []common.Output{
+				{Name: "chunk", Shape: []int64{1, 2}, Datatype: datatype.Bytes, Content: common.Content{StringContents: []string{"a1", "a2"}}},
+				{Name: "chunk", Shape: []int64{2, 3}, Datatype: datatype.Bytes, Content: common.Content{StringContents: []string{"b1", "b2", "b3", "c1", "c2", "c3"}}},
+				{Name: "chunk", Shape: []int64{1, 1}, Datatype: datatype.Bytes, Content: common.Content{StringContents: []string{"d1"}}},
+			},
+			expected: common.Output{
+				Name:     "chunk",
+				Shape:    []int64{4, 3},
+				Datatype: datatype.Bytes,
+				Content: common.Content{StringContents: []string{
+					"a1", "a2", common.OutputPadValue,
+					"b1", "b2", "b3",
+					"c1", "c2", "c3",
+					"d1", common.OutputPadValue, common.OutputPadValue,
+				}},
+			},
+		},
+		{
+			name: "FP32 of uneven length",
+			responses: []common.Output{
+				{Name: "token_embedding", Shape: []int64{1, 1, 2}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{1, 2}}},
+				{Name: "token_embedding", Shape: []int64{1, 2, 2}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{3, 4, 5, 6}}},
+			},
+			expected: common.Output{
+				Name:     "token_embedding",
+				Shape:    []int64{2, 2, 2},
+				Datatype: datatype.Fp32,
+				Content:  common.Content{Fp32Contents: []float32{1, 2, 0, 0, 3, 4, 5, 6}},
+			},
+		},
+		{
+			name: "FP32 of even length",
+			responses: []common.Output{
+				{Name: "embedding", Shape: []int64{2, 2}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{1, 2, 3, 4}}},
+				{Name: "embedding", Shape: []int64{1, 2}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{5, 6}}},
+			},
+			expected: common.Output{
+				Name:     "embedding",
+				Shape:    []int64{3, 2},
+				Datatype: datatype.Fp32,
+				Content:  common.Content{Fp32Contents: []float32{1, 2, 3, 4, 5, 6}},
+			},
+		},
+		{
+			name: "rank mismatch",
+			responses: []common.Output{
+				{Name: "embedding", Shape: []int64{1, 2}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{1, 2}}},
+				{Name: "embedding", Shape: []int64{2}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{3, 4}}},
+			},
+			err: "cannot merge output embedding: datatypes or ranks differ across split requests",
+		},
+		{
+			name: "element count mismatch",
+			responses: []common.Output{
+				{Name: "embedding", Shape: []int64{1, 2}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{1, 2}}},
+				{Name: "embedding", Shape: []int64{1, 1}, Datatype: datatype.Fp32, Content: common.Content{Fp32Contents: []float32{3, 4}}},
+			},
+			err: "cannot merge output embedding: element count does not match the shape",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := make([]*common.InferResponse, len(tt.responses))
+			for i, output := range tt.responses {
+				responses[i] = &common.InferResponse{ID: "request", Outputs: []common.Output{output}}
+			}
+
+			merged, err := mergeInferResponses(responses)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff([]common.Output{tt.expected}, merged.Outputs); diff != "" {
+				t.Errorf("unexpected merged outputs (-expected +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package requestergrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	requestergrpc "github.com/clinia/models-client-go/cliniamodel/requestergrpc/gen"
+)
+
+// Statistics implements common.Requester.
+func (r *requester) Statistics(ctx context.Context, modelName string, modelVersion string) ([]common.ModelStatistics, error) {
+	// The model name and version can only be formatted when both are known. When only one of them is
+	// given, we fetch the statistics of all models and filter them below.
+	statsReq := &requestergrpc.ModelStatisticsRequest{}
+	if modelName != "" && modelVersion != "" {
+		statsReq.Name, statsReq.Version = r.naming.Format(modelName, modelVersion)
+	}
+
+	res, err := r.inferenceServiceClient.ModelStatistics(ctx, statsReq)
+	if err != nil {
+		return nil, err
+	}
+	collectedAt := time.Now()
+
+	stats := make([]common.ModelStatistics, 0, len(res.ModelStats))
+	for _, modelStats := range res.ModelStats {
+		name, version := r.naming.Parse(modelStats.GetName(), modelStats.GetVersion())
+		if !r.matchesStatistics(modelStats, name, version, modelName, modelVersion) {
+			continue
+		}
+		// The statistics of the requested model version are reported under its name, whatever the naming scheme.
+		if modelName != "" && modelVersion != "" {
+			name, version = modelName, modelVersion
+		}
+
+		stats = append(stats, toModelStatistics(name, version, modelStats, collectedAt))
+	}
+
+	return stats, nil
+}
+
+// matchesStatistics reports whether the statistics of the model parsed as name and version belong to the requested
+// model name and version, either of which may be empty to match any. When both are given, the server-side names
+// are compared, since some naming schemes cannot recover the model name and version (e.g. ModelNamingFunc).
+func (r *requester) matchesStatistics(modelStats *requestergrpc.ModelStatistics, name, version, modelName, modelVersion string) bool {
+	if modelName != "" && modelVersion != "" {
+		serverName, serverVersion := r.naming.Format(modelName, modelVersion)
+		return serverName == modelStats.GetName() && serverVersion == modelStats.GetVersion()
+	}
+
+	return (modelName == "" || name == modelName) && (modelVersion == "" || version == modelVersion)
+}
+
+// toModelStatistics converts the statistics reported by Triton to common.ModelStatistics.
+func toModelStatistics(modelName string, modelVersion string, stats *requestergrpc.ModelStatistics, collectedAt time.Time) common.ModelStatistics {
+	inferStats := stats.GetInferenceStats()
+
+	batches := make([]common.BatchStatistics, len(stats.GetBatchStats()))
+	for i, batchStats := range stats.GetBatchStats() {
+		batches[i] = common.BatchStatistics{
+			BatchSize:     batchStats.GetBatchSize(),
+			ComputeInput:  toStatisticDuration(batchStats.GetComputeInput()),
+			ComputeInfer:  toStatisticDuration(batchStats.GetComputeInfer()),
+			ComputeOutput: toStatisticDuration(batchStats.GetComputeOutput()),
+		}
+	}
+
+	var lastInference time.Time
+	if stats.GetLastInference() > 0 {
+		// #nosec G115
+		lastInference = time.UnixMilli(int64(stats.GetLastInference()))
+	}
+
+	return common.ModelStatistics{
+		ModelName:      modelName,
+		ModelVersion:   modelVersion,
+		LastInference:  lastInference,
+		InferenceCount: stats.GetInferenceCount(),
+		ExecutionCount: stats.GetExecutionCount(),
+		Inference: common.InferStatistics{
+			Success:       toStatisticDuration(inferStats.GetSuccess()),
+			Fail:          toStatisticDuration(inferStats.GetFail()),
+			Queue:         toStatisticDuration(inferStats.GetQueue()),
+			ComputeInput:  toStatisticDuration(inferStats.GetComputeInput()),
+			ComputeInfer:  toStatisticDuration(inferStats.GetComputeInfer()),
+			ComputeOutput: toStatisticDuration(inferStats.GetComputeOutput()),
+			CacheHit:      toStatisticDuration(inferStats.GetCacheHit()),
+			CacheMiss:     toStatisticDuration(inferStats.GetCacheMiss()),
+		},
+		Batches:     batches,
+		CollectedAt: collectedAt,
+	}
+}
+
+func toStatisticDuration(d *requestergrpc.StatisticDuration) common.StatisticDuration {
+	return common.StatisticDuration{
+		Count: d.GetCount(),
+		// #nosec G115
+		Total: time.Duration(d.GetNs()),
+	}
+}
@@ -0,0 +1,143 @@
+package requestergrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	requestergrpc "github.com/clinia/models-client-go/cliniamodel/requestergrpc/gen"
+	"google.golang.org/grpc"
+)
+
+// stream is a common.InferStream over the Triton ModelStreamInfer RPC. Responses are matched with their
+// request by ID, since the model server does not order the responses of requests to different models.
+type stream struct {
+	r      *requester
+	stream grpc.BidiStreamingClient[requestergrpc.ModelInferRequest, requestergrpc.ModelStreamInferResponse]
+
+	// pending holds the requests sent on the stream whose last response is not yet received, by ID.
+	mu      sync.Mutex
+	pending map[string]common.InferRequest
+}
+
+var _ common.InferStream = (*stream)(nil)
+
+// Stream implements common.Requester.
+func (r *requester) Stream(ctx context.Context) (common.InferStream, error) {
+	// The requests of a stream have their own IDs, only the metadata and the ID carried by the context are sent.
+	id, _ := common.RequestIDFromContext(ctx)
+	s, err := r.inferenceServiceClient.ModelStreamInfer(withOutgoingMetadata(ctx, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &stream{
+		r:       r,
+		stream:  s,
+		pending: make(map[string]common.InferRequest),
+	}, nil
+}
+
+// Send implements common.InferStream. Requests made without an ID get a random one, since the responses are
+// matched with their request by ID.
+func (s *stream) Send(req common.InferRequest) error {
+	if req.ID == "" {
+		req.ID = common.UUIDRequestIDGenerator()
+	}
+
+	inferReq, err := s.r.newModelInferRequest(req)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if _, ok := s.pending[req.ID]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("a request with ID %q is already pending on the stream", req.ID)
+	}
+	s.pending[req.ID] = req
+	s.mu.Unlock()
+
+	if err := s.stream.Send(inferReq); err != nil {
+		s.mu.Lock()
+		delete(s.pending, req.ID)
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Recv implements common.InferStream.
+func (s *stream) Recv() (*common.InferResponse, error) {
+	for {
+		res, err := s.stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if res.InferResponse == nil && res.ErrorMessage == "" {
+			return nil, errors.New("received an empty response")
+		}
+
+		// The last response of a request to a decoupled model can be empty, only marking the end of the request.
+		inferRes, err := s.recv(res)
+		if inferRes == nil && err == nil {
+			continue
+		}
+
+		return inferRes, err
+	}
+}
+
+// recv matches the response with its request and decodes it. It returns neither a response nor an error
+// for an empty final response.
+func (s *stream) recv(res *requestergrpc.ModelStreamInferResponse) (*common.InferResponse, error) {
+	id := res.InferResponse.GetId()
+	final := isFinalResponse(res.InferResponse)
+
+	s.mu.Lock()
+	req, ok := s.pending[id]
+	if ok && (final || res.ErrorMessage != "") {
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		if res.ErrorMessage != "" {
+			return nil, fmt.Errorf("inference failed: %s", res.ErrorMessage)
+		}
+		return nil, fmt.Errorf("received a response to an unknown request %q", id)
+	}
+
+	if res.ErrorMessage != "" {
+		return nil, wrapRequestError(req.ID, fmt.Errorf("inference failed: %s", res.ErrorMessage))
+	}
+	if final && len(res.InferResponse.Outputs) == 0 && len(req.OutputKeys) > 0 {
+		return nil, nil
+	}
+
+	inferRes, err := s.r.parseModelInferResponse(req, res.InferResponse)
+	if err != nil {
+		return nil, wrapRequestError(req.ID, err)
+	}
+
+	return inferRes, nil
+}
+
+// isFinalResponse reports whether the response is the last one of its request. Only decoupled models mark
+// their responses, the single response of the other models is final.
+func isFinalResponse(res *requestergrpc.ModelInferResponse) bool {
+	param, ok := res.GetParameters()[common.ParameterFinalResponse]
+	if !ok {
+		return true
+	}
+
+	return param.GetBoolParam()
+}
+
+// CloseSend implements common.InferStream.
+func (s *stream) CloseSend() error {
+	return s.stream.CloseSend()
+}
@@ -0,0 +1,66 @@
+package requestergrpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier(nil)
+
+// Get implements propagation.TextMapCarrier.
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Set implements propagation.TextMapCarrier.
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys implements propagation.TextMapCarrier.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// injectTraceContext returns a context whose outgoing gRPC metadata carries the trace context of ctx.
+func injectTraceContext(ctx context.Context, propagator propagation.TextMapPropagator) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	propagator.Inject(ctx, metadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// unaryTracingInterceptor propagates the trace context of the unary RPCs to the model server.
+func unaryTracingInterceptor(propagator propagation.TextMapPropagator) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(injectTraceContext(ctx, propagator), method, req, reply, cc, opts...)
+	}
+}
+
+// streamTracingInterceptor propagates the trace context of the streaming RPCs to the model server.
+func streamTracingInterceptor(propagator propagation.TextMapPropagator) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(injectTraceContext(ctx, propagator), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,111 @@
+package requestergrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	gen "github.com/clinia/models-client-go/cliniamodel/requestergrpc/gen"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// tracingServer is an inference server recording the trace context it receives.
+type tracingServer struct {
+	gen.UnimplementedGRPCInferenceServiceServer
+
+	traceparents chan string
+}
+
+func (s *tracingServer) ModelInfer(ctx context.Context, req *gen.ModelInferRequest) (*gen.ModelInferResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	traceparent := ""
+	if values := md.Get("traceparent"); len(values) > 0 {
+		traceparent = values[0]
+	}
+	s.traceparents <- traceparent
+
+	return &gen.ModelInferResponse{Id: req.Id, ModelName: req.ModelName, ModelVersion: req.ModelVersion}, nil
+}
+
+func TestInferTracing(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	srv := &tracingServer{traceparents: make(chan string, 1)}
+	gen.RegisterGRPCInferenceServiceServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	requester, err := NewRequester(context.Background(), common.RequesterConfig{
+		Host: common.Host{
+			Url:    "127.0.0.1",
+			Port:   lis.Addr().(*net.TCPAddr).Port,
+			Scheme: common.HTTP,
+		},
+		TracerProvider: tp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = requester.Close() })
+
+	_, err = requester.Infer(context.Background(), common.InferRequest{
+		ID:           "request-1",
+		ModelName:    "embedder",
+		ModelVersion: "1",
+		Inputs: []common.Input{{
+			Name:     "text",
+			Datatype: datatype.Bytes,
+			Content:  common.Content{StringContents: []string{"clinia", "montreal"}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "ModelInfer" {
+		t.Errorf("expected span ModelInfer, got %s", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("expected a client span, got %s", span.SpanKind)
+	}
+
+	attrs := attribute.NewSet(span.Attributes...)
+	for key, expected := range map[attribute.Key]attribute.Value{
+		common.AttributeModelName:    attribute.StringValue("embedder"),
+		common.AttributeModelVersion: attribute.StringValue("1"),
+		common.AttributeBatchSize:    attribute.IntValue(2),
+		common.AttributeOutcome:      attribute.StringValue(common.OutcomeSuccess),
+	} {
+		if value, ok := attrs.Value(key); !ok || value != expected {
+			t.Errorf("expected attribute %s=%s, got %s", key, expected.Emit(), value.Emit())
+		}
+	}
+	if value, ok := attrs.Value(common.AttributePayloadBytes); !ok || value.AsInt64() <= 0 {
+		t.Errorf("expected a positive %s attribute, got %s", common.AttributePayloadBytes, value.Emit())
+	}
+
+	// The model server receives the trace context of the span in the W3C format.
+	expected := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if traceparent := <-srv.traceparents; traceparent != expected {
+		t.Errorf("expected traceparent %s, got %s", expected, traceparent)
+	}
+}
@@ -0,0 +1,63 @@
+package filesvcclient
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WithLogger logs the structured events of every request with the given logger. Requests taking
+// longer than slowThreshold are logged as warnings; set it to 0 to disable slow request logging.
+// The request bodies are never logged.
+// It wraps the HTTP client configured so far, so it must be passed after WithHTTPClient.
+func WithLogger(logger *slog.Logger, slowThreshold time.Duration) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			return nil
+		}
+		if c.Client == nil {
+			c.Client = &http.Client{}
+		}
+		c.Client = &loggingDoer{
+			next:          c.Client,
+			logger:        logger,
+			slowThreshold: slowThreshold,
+		}
+		return nil
+	}
+}
+
+// loggingDoer is an HttpRequestDoer logging the completion of every request.
+type loggingDoer struct {
+	next          HttpRequestDoer
+	logger        *slog.Logger
+	slowThreshold time.Duration
+}
+
+func (d *loggingDoer) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := d.next.Do(req)
+	duration := time.Since(start)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Int64("payload_bytes", req.ContentLength),
+		slog.Duration("duration", duration),
+	}
+
+	ctx := req.Context()
+	switch {
+	case err != nil:
+		d.logger.LogAttrs(ctx, slog.LevelDebug, "file service request failed", append(attrs, slog.Any("error", err))...)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		d.logger.LogAttrs(ctx, slog.LevelWarn, "file service request failed", append(attrs, slog.Int("status", resp.StatusCode))...)
+	case d.slowThreshold > 0 && duration > d.slowThreshold:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode), slog.Duration("threshold", d.slowThreshold))
+		d.logger.LogAttrs(ctx, slog.LevelWarn, "slow file service request", attrs...)
+	default:
+		d.logger.LogAttrs(ctx, slog.LevelDebug, "file service request completed", append(attrs, slog.Int("status", resp.StatusCode))...)
+	}
+
+	return resp, err
+}
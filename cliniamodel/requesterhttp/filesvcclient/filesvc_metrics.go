@@ -0,0 +1,61 @@
+package filesvcclient
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// WithMetrics records the metrics of every request with the given recorder. The operation of the
+// requests is their method and path (e.g. "POST /v1/pdf/split/images").
+// It wraps the HTTP client configured so far, so it must be passed after WithHTTPClient.
+func WithMetrics(recorder common.MetricsRecorder) ClientOption {
+	return func(c *Client) error {
+		if recorder == nil {
+			return nil
+		}
+		if c.Client == nil {
+			c.Client = &http.Client{}
+		}
+		c.Client = &metricsDoer{
+			next:     c.Client,
+			recorder: recorder,
+		}
+		return nil
+	}
+}
+
+// metricsDoer is an HttpRequestDoer recording the metrics of every request.
+type metricsDoer struct {
+	next     HttpRequestDoer
+	recorder common.MetricsRecorder
+}
+
+func (d *metricsDoer) Do(req *http.Request) (*http.Response, error) {
+	operation := fmt.Sprintf("%s %s", req.Method, req.URL.Path)
+
+	d.recorder.AddInFlight(operation, "", "", 1)
+	start := time.Now()
+	resp, err := d.next.Do(req)
+	duration := time.Since(start)
+	d.recorder.AddInFlight(operation, "", "", -1)
+
+	outcome := common.Outcome(err)
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		outcome = common.OutcomeError
+	}
+	obs := common.RequestObservation{
+		Operation: operation,
+		Outcome:   outcome,
+		Duration:  duration,
+	}
+	// The content length is -1 when unknown (e.g. streamed bodies).
+	if req.ContentLength > 0 {
+		obs.PayloadBytes = int(req.ContentLength)
+	}
+	d.recorder.ObserveRequest(obs)
+
+	return resp, err
+}
@@ -0,0 +1,58 @@
+package filesvcclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithTimeout bounds the duration of every request, including the reading of its response body. A deadline
+// already set on the context of a request is kept if it is earlier.
+// It wraps the HTTP client configured so far, so it must be passed after WithHTTPClient.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout <= 0 {
+			return nil
+		}
+		if c.Client == nil {
+			c.Client = &http.Client{}
+		}
+		c.Client = &timeoutDoer{
+			next:    c.Client,
+			timeout: timeout,
+		}
+		return nil
+	}
+}
+
+// timeoutDoer is an HttpRequestDoer bounding the duration of every request.
+type timeoutDoer struct {
+	next    HttpRequestDoer
+	timeout time.Duration
+}
+
+func (d *timeoutDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), d.timeout)
+	resp, err := d.next.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The context must outlive Do so that the body can be read. It is released once the body is closed.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody is a response body releasing the context of its request when closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
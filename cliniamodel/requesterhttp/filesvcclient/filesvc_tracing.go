@@ -0,0 +1,76 @@
+package filesvcclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracing creates a client span for every request and propagates the trace context in the request headers.
+// The global tracer provider is used if tp is nil, and the W3C trace-context propagator if propagator is nil.
+// It wraps the HTTP client configured so far, so it must be passed after WithHTTPClient.
+func WithTracing(tp trace.TracerProvider, propagator propagation.TextMapPropagator) ClientOption {
+	return func(c *Client) error {
+		if c.Client == nil {
+			c.Client = &http.Client{}
+		}
+		c.Client = &tracingDoer{
+			next:       c.Client,
+			tracer:     common.Tracer(tp),
+			propagator: common.Propagator(propagator),
+		}
+		return nil
+	}
+}
+
+// tracingDoer is an HttpRequestDoer creating a client span for every request.
+type tracingDoer struct {
+	next       HttpRequestDoer
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func (d *tracingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := d.tracer.Start(req.Context(), fmt.Sprintf("FileService %s %s", req.Method, req.URL.Path),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", redactURL(req.URL)),
+			common.AttributePayloadBytes.Int64(req.ContentLength),
+		),
+	)
+
+	// The request is cloned since a Doer must not modify the request it is given.
+	req = req.Clone(ctx)
+	d.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.next.Do(req)
+	spanErr := err
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			spanErr = fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+	}
+	common.EndSpan(span, spanErr)
+
+	return resp, err
+}
+
+// redactURL returns the URL without its user info, query and fragment, which may carry credentials (e.g.
+// signed URLs).
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	redacted.ForceQuery = false
+	redacted.Fragment = ""
+	redacted.RawFragment = ""
+	redacted.User = nil
+
+	return redacted.String()
+}
@@ -0,0 +1,69 @@
+package filesvcclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracing(t *testing.T) {
+	traceparents := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	client, err := NewClient(server.URL, WithTracing(tp, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The query of signed URLs carries credentials, which must not be recorded.
+	signQuery := func(_ context.Context, req *http.Request) error {
+		req.URL.RawQuery = "token=secret"
+		return nil
+	}
+	resp, err := client.SplitToImagesWithBody(context.Background(), "application/pdf", strings.NewReader("%PDF"), signQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if expected := "FileService POST " + resp.Request.URL.Path; span.Name != expected {
+		t.Errorf("expected span %s, got %s", expected, span.Name)
+	}
+
+	attrs := attribute.NewSet(span.Attributes...)
+	if value, _ := attrs.Value("url.full"); value.AsString() != server.URL+resp.Request.URL.Path {
+		t.Errorf("expected url.full without the query, got %s", value.Emit())
+	}
+	if value, _ := attrs.Value(common.AttributePayloadBytes); value.AsInt64() != int64(len("%PDF")) {
+		t.Errorf("expected %s=%d, got %s", common.AttributePayloadBytes, len("%PDF"), value.Emit())
+	}
+	if value, _ := attrs.Value("http.response.status_code"); value.AsInt64() != http.StatusOK {
+		t.Errorf("expected http.response.status_code=%d, got %s", http.StatusOK, value.Emit())
+	}
+
+	// The file service receives the trace context of the span in the W3C format.
+	expected := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if traceparent := <-traceparents; traceparent != expected {
+		t.Errorf("expected traceparent %s, got %s", expected, traceparent)
+	}
+}
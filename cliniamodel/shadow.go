@@ -0,0 +1,367 @@
+package cliniamodel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
+
+// Operations compared by the shadows.
+const (
+	ShadowOperationEmbed = "embed"
+	ShadowOperationRank  = "rank"
+)
+
+// ShadowConfig configures the mirroring of live requests to a candidate model.
+type ShadowConfig struct {
+	// Name identifies the comparison in the results (e.g. "embedder-v4").
+	Name string
+	// SampleRate is the fraction of the requests mirrored to the candidate, between 0 and 1.
+	SampleRate float64
+	// Timeout bounds the duration of the candidate requests. Defaults to 30 seconds.
+	Timeout time.Duration
+	// MaxInFlight is the maximum number of candidate requests in flight. Sampled requests are dropped when
+	// it is reached, so that the candidate never slows down the primary traffic. Defaults to 8.
+	MaxInFlight int
+	// TopK is the number of top ranked passages compared by TopKOverlap. Defaults to 10.
+	TopK int
+	// Reporter receives the result of every comparison. It is called from the goroutine of the candidate request.
+	Reporter ShadowReporter
+}
+
+// ShadowResult is the comparison of a primary response with the response of the candidate to the same request.
+type ShadowResult struct {
+	// Name is the name of the shadow.
+	Name string `json:"name"`
+	// Operation is the compared operation, ShadowOperationEmbed or ShadowOperationRank.
+	Operation string `json:"operation"`
+	// RequestID is the ID of the primary request.
+	RequestID string `json:"requestId"`
+	// Time is the time at which the primary request was made.
+	Time time.Time `json:"time"`
+	// BatchSize is the number of texts of the request.
+	BatchSize int `json:"batchSize"`
+	// PrimaryLatency and CandidateLatency are the durations of the primary and candidate requests.
+	PrimaryLatency   time.Duration `json:"primaryLatency"`
+	CandidateLatency time.Duration `json:"candidateLatency"`
+	// LatencyDelta is the candidate latency minus the primary latency.
+	LatencyDelta time.Duration `json:"latencyDelta"`
+	// CandidateError is the error of the candidate request, or of the comparison. The comparison is not set if not empty.
+	CandidateError string `json:"candidateError,omitempty"`
+
+	// Embeddings compares the embeddings, for the embed operation.
+	Embeddings *EmbeddingComparison `json:"embeddings,omitempty"`
+	// Ranking compares the rankings, for the rank operation.
+	Ranking *RankingComparison `json:"ranking,omitempty"`
+}
+
+// EmbeddingComparison compares the primary and candidate embeddings of the texts of a request.
+type EmbeddingComparison struct {
+	// MeanCosineSimilarity is the mean of the cosine similarities of the primary and candidate embeddings of each text.
+	MeanCosineSimilarity float64 `json:"meanCosineSimilarity"`
+	// MinCosineSimilarity is the minimum of the cosine similarities of the primary and candidate embeddings of each text.
+	MinCosineSimilarity float64 `json:"minCosineSimilarity"`
+}
+
+// RankingComparison compares the primary and candidate rankings of the passages of a request.
+type RankingComparison struct {
+	// KendallTau is the Kendall rank correlation (tau-b) of the primary and candidate scores, between -1 and 1.
+	KendallTau float64 `json:"kendallTau"`
+	// TopK is the number of top passages compared by TopKOverlap, capped to the number of passages.
+	TopK int `json:"topK"`
+	// TopKOverlap is the fraction of the top TopK passages of the primary ranking that are also in the top TopK
+	// passages of the candidate ranking.
+	TopKOverlap float64 `json:"topKOverlap"`
+}
+
+// ShadowReporter receives the results of the shadow comparisons.
+type ShadowReporter interface {
+	Report(result ShadowResult)
+}
+
+// ShadowReporterFunc is a function implementing ShadowReporter.
+type ShadowReporterFunc func(result ShadowResult)
+
+// Report implements ShadowReporter.
+func (f ShadowReporterFunc) Report(result ShadowResult) {
+	f(result)
+}
+
+// NewShadowReportWriter returns a reporter writing the results to w as JSON lines. Write errors are ignored.
+func NewShadowReportWriter(w io.Writer) ShadowReporter {
+	return &shadowReportWriter{encoder: json.NewEncoder(w)}
+}
+
+type shadowReportWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (w *shadowReportWriter) Report(result ShadowResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_ = w.encoder.Encode(result)
+}
+
+// shadow mirrors a sample of the requests to a candidate, asynchronously.
+type shadow struct {
+	cfg      ShadowConfig
+	inFlight chan struct{}
+
+	// mu guards closed, so that no candidate request is added to wg once close waits for it.
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newShadow(cfg ShadowConfig) *shadow {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 8
+	}
+	if cfg.TopK <= 0 {
+		cfg.TopK = 10
+	}
+
+	return &shadow{
+		cfg:      cfg,
+		inFlight: make(chan struct{}, cfg.MaxInFlight),
+	}
+}
+
+// candidateFunc makes the candidate request. It returns the comparison of the candidate response with the
+// primary response, which fills the statistics of the result.
+type candidateFunc func(ctx context.Context) (compare func(result *ShadowResult) error, err error)
+
+// sample decides whether a request is mirrored to the candidate. It reserves a slot for the candidate
+// request, which must then be made with mirror.
+func (s *shadow) sample() bool {
+	if s.cfg.Reporter == nil || s.cfg.SampleRate <= 0 || rand.Float64() >= s.cfg.SampleRate {
+		return false
+	}
+
+	select {
+	case s.inFlight <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// mirror runs the candidate request of a sampled request in the background, then reports the result. The
+// candidate request is detached from the cancellation of ctx, since the primary request completes first.
+// Sampled requests are dropped once the shadow is closed.
+func (s *shadow) mirror(ctx context.Context, result ShadowResult, candidate candidateFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		<-s.inFlight
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.inFlight }()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.Timeout)
+		defer cancel()
+
+		start := time.Now()
+		compare, err := candidate(ctx)
+		result.CandidateLatency = time.Since(start)
+		result.LatencyDelta = result.CandidateLatency - result.PrimaryLatency
+		if err == nil {
+			err = compare(&result)
+		}
+		if err != nil {
+			result.CandidateError = err.Error()
+		}
+
+		s.cfg.Reporter.Report(result)
+	}()
+}
+
+// close stops mirroring the requests and waits for the candidate requests in flight.
+func (s *shadow) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// candidateOptions returns the call options of the candidate request mirroring the request with the given
+// ID. The candidate request gets its own ID, derived from the primary one.
+func (s *shadow) candidateOptions(requestID string, opts []common.CallOption) []common.CallOption {
+	return append(slices.Clip(opts), common.WithRequestID(requestID+":shadow"))
+}
+
+// result starts the result of the comparison of a primary request made at start.
+func (s *shadow) result(operation, requestID string, start time.Time, batchSize int) ShadowResult {
+	return ShadowResult{
+		Name:           s.cfg.Name,
+		Operation:      operation,
+		RequestID:      requestID,
+		Time:           start,
+		BatchSize:      batchSize,
+		PrimaryLatency: time.Since(start),
+	}
+}
+
+// ShadowEmbedder is an EmbedderModel answering with a primary model and mirroring a sample of the requests
+// to a candidate model to compare their embeddings.
+type ShadowEmbedder struct {
+	primary   EmbedderModel
+	candidate EmbedderModel
+	shadow    *shadow
+}
+
+var _ EmbedderModel = (*ShadowEmbedder)(nil)
+
+// NewShadowEmbedder creates an embedder answering with the primary model and mirroring a sample of the
+// requests to the candidate model. The candidate never affects the responses of the primary model.
+func NewShadowEmbedder(primary, candidate EmbedderModel, cfg ShadowConfig) *ShadowEmbedder {
+	return &ShadowEmbedder{
+		primary:   primary,
+		candidate: candidate,
+		shadow:    newShadow(cfg),
+	}
+}
+
+// Embed implements the EmbedderModel interface.
+func (s *ShadowEmbedder) Embed(ctx context.Context, req EmbedRequest, opts ...common.CallOption) (*EmbedResponse, error) {
+	start := time.Now()
+	res, err := s.primary.Embed(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !s.shadow.sample() {
+		return res, nil
+	}
+
+	// The texts and parameters are copied since the caller may reuse them once Embed returns.
+	candidateReq := EmbedRequest{
+		Texts:      slices.Clone(req.Texts),
+		Parameters: maps.Clone(req.Parameters),
+	}
+	candidateOpts := s.shadow.candidateOptions(res.ID, opts)
+	result := s.shadow.result(ShadowOperationEmbed, res.ID, start, len(req.Texts))
+	// The primary embeddings are copied since the caller may modify them (e.g. normalize them in place).
+	primaryEmbeddings := cloneEmbeddings(res.Embeddings)
+	s.shadow.mirror(ctx, result, func(ctx context.Context) (func(*ShadowResult) error, error) {
+		candidateRes, err := s.candidate.Embed(ctx, candidateReq, candidateOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(result *ShadowResult) error {
+			return compareEmbeddings(result, primaryEmbeddings, candidateRes.Embeddings)
+		}, nil
+	})
+
+	return res, nil
+}
+
+// Ready implements the EmbedderModel interface. It checks the readiness of the primary model only.
+func (s *ShadowEmbedder) Ready(ctx context.Context) error {
+	return s.primary.Ready(ctx)
+}
+
+// Close stops mirroring the requests and waits for the candidate requests in flight.
+func (s *ShadowEmbedder) Close() {
+	s.shadow.close()
+}
+
+// ShadowRanker is a RankerModel answering with a primary model and mirroring a sample of the requests to a
+// candidate model to compare their rankings.
+type ShadowRanker struct {
+	primary   RankerModel
+	candidate RankerModel
+	shadow    *shadow
+}
+
+var _ RankerModel = (*ShadowRanker)(nil)
+
+// NewShadowRanker creates a ranker answering with the primary model and mirroring a sample of the requests
+// to the candidate model. The candidate never affects the responses of the primary model.
+func NewShadowRanker(primary, candidate RankerModel, cfg ShadowConfig) *ShadowRanker {
+	return &ShadowRanker{
+		primary:   primary,
+		candidate: candidate,
+		shadow:    newShadow(cfg),
+	}
+}
+
+// Rank implements the RankerModel interface.
+func (s *ShadowRanker) Rank(ctx context.Context, req RankRequest, opts ...common.CallOption) (*RankResponse, error) {
+	start := time.Now()
+	res, err := s.primary.Rank(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !s.shadow.sample() {
+		return res, nil
+	}
+
+	// The texts and parameters are copied since the caller may reuse them once Rank returns.
+	candidateReq := RankRequest{
+		Query:      req.Query,
+		Texts:      slices.Clone(req.Texts),
+		Parameters: maps.Clone(req.Parameters),
+	}
+	candidateOpts := s.shadow.candidateOptions(res.ID, opts)
+	result := s.shadow.result(ShadowOperationRank, res.ID, start, len(req.Texts))
+	// The primary scores are copied since the caller may modify them (e.g. sort them in place).
+	primaryScores := slices.Clone(res.Scores)
+	topK := s.shadow.cfg.TopK
+	s.shadow.mirror(ctx, result, func(ctx context.Context) (func(*ShadowResult) error, error) {
+		candidateRes, err := s.candidate.Rank(ctx, candidateReq, candidateOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(result *ShadowResult) error {
+			return compareScores(result, primaryScores, candidateRes.Scores, topK)
+		}, nil
+	})
+
+	return res, nil
+}
+
+// Ready implements the RankerModel interface. It checks the readiness of the primary model only.
+func (s *ShadowRanker) Ready(ctx context.Context) error {
+	return s.primary.Ready(ctx)
+}
+
+// Close stops mirroring the requests and waits for the candidate requests in flight.
+func (s *ShadowRanker) Close() {
+	s.shadow.close()
+}
+
+// cloneEmbeddings returns a deep copy of the embeddings, backed by a single allocation.
+func cloneEmbeddings(embeddings [][]float32) [][]float32 {
+	size := 0
+	for _, embedding := range embeddings {
+		size += len(embedding)
+	}
+
+	values := make([]float32, 0, size)
+	cloned := make([][]float32, len(embeddings))
+	for i, embedding := range embeddings {
+		values = append(values, embedding...)
+		cloned[i] = values[len(values)-len(embedding) : len(values) : len(values)]
+	}
+
+	return cloned
+}
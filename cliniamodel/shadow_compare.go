@@ -0,0 +1,140 @@
+package cliniamodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// compareEmbeddings sets the cosine similarities between the primary and candidate embeddings of each text.
+func compareEmbeddings(result *ShadowResult, primary, candidate [][]float32) error {
+	if len(primary) != len(candidate) {
+		return fmt.Errorf("candidate returned %d embeddings, expected %d", len(candidate), len(primary))
+	}
+	if len(primary) == 0 {
+		result.Embeddings = &EmbeddingComparison{MeanCosineSimilarity: 1, MinCosineSimilarity: 1}
+		return nil
+	}
+
+	sum, minimum := 0.0, math.Inf(1)
+	for i := range primary {
+		if len(primary[i]) != len(candidate[i]) {
+			return fmt.Errorf("candidate embedding dimension %d does not match the primary dimension %d", len(candidate[i]), len(primary[i]))
+		}
+
+		similarity := cosineSimilarity(primary[i], candidate[i])
+		sum += similarity
+		minimum = math.Min(minimum, similarity)
+	}
+
+	result.Embeddings = &EmbeddingComparison{
+		MeanCosineSimilarity: sum / float64(len(primary)),
+		MinCosineSimilarity:  minimum,
+	}
+	return nil
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors of the same dimension, or 0 if one is null.
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// compareScores sets the rank correlation and the top-k overlap of the primary and candidate scores of the
+// same passages.
+func compareScores(result *ShadowResult, primary, candidate []float32, topK int) error {
+	if len(primary) != len(candidate) {
+		return fmt.Errorf("candidate returned %d scores, expected %d", len(candidate), len(primary))
+	}
+
+	k := min(topK, len(primary))
+	result.Ranking = &RankingComparison{
+		KendallTau:  kendallTau(primary, candidate),
+		TopK:        k,
+		TopKOverlap: topKOverlap(primary, candidate, k),
+	}
+	return nil
+}
+
+// kendallTau returns the Kendall tau-b rank correlation of two score lists, which accounts for ties.
+// It returns 1 if either list has fewer than two distinct scores.
+func kendallTau(a, b []float32) float64 {
+	var concordant, discordant, tiesA, tiesB float64
+	for i := range a {
+		for j := i + 1; j < len(a); j++ {
+			da, db := compareFloat32(a[i], a[j]), compareFloat32(b[i], b[j])
+			switch {
+			case da == 0 && db == 0:
+			case da == 0:
+				tiesA++
+			case db == 0:
+				tiesB++
+			case da == db:
+				concordant++
+			default:
+				discordant++
+			}
+		}
+	}
+
+	denominator := math.Sqrt((concordant + discordant + tiesA) * (concordant + discordant + tiesB))
+	if denominator == 0 {
+		return 1
+	}
+
+	return (concordant - discordant) / denominator
+}
+
+func compareFloat32(x, y float32) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// topKOverlap returns the fraction of the k best scored passages of a that are among the k best of b.
+func topKOverlap(a, b []float32, k int) float64 {
+	if k == 0 {
+		return 1
+	}
+
+	top := make(map[int]struct{}, k)
+	for _, i := range topIndices(a, k) {
+		top[i] = struct{}{}
+	}
+
+	overlap := 0
+	for _, i := range topIndices(b, k) {
+		if _, ok := top[i]; ok {
+			overlap++
+		}
+	}
+
+	return float64(overlap) / float64(k)
+}
+
+// topIndices returns the indices of the k highest scores, ties being broken by index.
+func topIndices(scores []float32, k int) []int {
+	indices := make([]int, len(scores))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return scores[indices[i]] > scores[indices[j]]
+	})
+
+	return indices[:k]
+}
@@ -1,19 +1,42 @@
 package cliniamodel
 
-import "context"
+import (
+	"context"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+)
 
 type SparseEmbedder interface {
 	// SparseEmbed returns the sparse embeddings of the given texts.
-	SparseEmbed(ctx context.Context, modelName, modelVersion string, req SparseEmbedRequest) (*SparseEmbedResponse, error)
+	SparseEmbed(ctx context.Context, modelName, modelVersion string, req SparseEmbedRequest, opts ...common.CallOption) (*SparseEmbedResponse, error)
 	// Ready checks if the model is ready to receive requests.
 	Ready(ctx context.Context, modelName, modelVersion string) error
+	// Model returns a handle on the given model version.
+	Model(modelName, modelVersion string) SparseEmbedderModel
+	// Alias returns a handle on the model version of the alias, resolved on every call by the model resolver
+	// of the client options.
+	Alias(alias string) SparseEmbedderModel
+}
+
+// SparseEmbedderModel is a handle on a model of the sparse embedder, bound to a model version or to an alias.
+type SparseEmbedderModel interface {
+	// SparseEmbed returns the sparse embeddings of the given texts.
+	SparseEmbed(ctx context.Context, req SparseEmbedRequest, opts ...common.CallOption) (*SparseEmbedResponse, error)
+	// Ready checks if the model is ready to receive requests.
+	Ready(ctx context.Context) error
 }
 
 type SparseEmbedRequest struct {
-	// ID is the unique identifier for the request.
+	// ID is the unique identifier for the request. If empty, the ID carried by the context (see
+	// common.ContextWithRequestID) is used, or a new one is generated (a random UUIDv4 by default).
 	ID string
 	// Texts is the list of texts to be embedded.
 	Texts []string
+	// Parameters are the optional parameters of the sparse embedder model (e.g. truncation mode).
+	Parameters common.Parameters
+	// Outputs are the auxiliary outputs to return in addition to the embeddings (e.g. OutputTokenCounts). The
+	// outputs that the model does not declare are not requested, and their fields of the response are left nil.
+	Outputs []AuxiliaryOutput
 }
 
 type SparseEmbedResponse struct {
@@ -22,4 +45,9 @@ type SparseEmbedResponse struct {
 	// Embeddings is the list of sparse embeddings for each text. Each embedding is a map
 	// of tokens to their corresponding float values.
 	Embeddings []map[string]float32
+	// TokenCounts is the number of tokens of each text, if requested with OutputTokenCounts.
+	TokenCounts []int
+	// Truncated reports whether each text was truncated to the maximum length of the model, if requested with
+	// OutputTruncated.
+	Truncated []bool
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/clinia/models-client-go/cliniamodel/common"
 	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -16,9 +17,20 @@ const (
 	sparseEmbedderInputDatatype        = datatype.Bytes
 )
 
+// sparseEmbedderTensors are the tensors of the sparse embedder models.
+var sparseEmbedderTensors = clientTensors{
+	inputs:    []tensor{{name: sparseEmbedderInputKey, datatype: sparseEmbedderInputDatatype}},
+	outputs:   []tensor{{name: sparseEmbedderOutputKey, datatype: datatype.Bytes}},
+	auxiliary: []AuxiliaryOutput{OutputTokenCounts, OutputTruncated},
+}
+
 // embedder is a struct that implements the SparseEmbedder interface.
 type sparseEmbedder struct {
-	requester common.Requester
+	requester  common.Requester
+	tracer     trace.Tracer
+	requestIDs common.RequestIDGenerator
+	models     common.ModelResolver
+	tensors    *tensorResolver
 }
 
 var _ SparseEmbedder = (*sparseEmbedder)(nil)
@@ -26,19 +38,43 @@ var _ SparseEmbedder = (*sparseEmbedder)(nil)
 // NewEmbedder creates a new instance of embedder.
 func NewSparseEmbedder(ctx context.Context, opts common.ClientOptions) SparseEmbedder {
 	return &sparseEmbedder{
-		requester: opts.Requester,
+		requester:  opts.Requester,
+		tracer:     common.Tracer(opts.TracerProvider),
+		requestIDs: opts.RequestIDGenerator,
+		models:     opts.ModelResolver,
+		tensors:    newTensorResolver(opts.Requester, opts.TensorMapping, opts.Logger),
 	}
 }
 
 // SparseEmbed generates embeddings for the given texts using the specified model and version.
-func (e *sparseEmbedder) SparseEmbed(ctx context.Context, modelName, modelVersion string, req SparseEmbedRequest) (*SparseEmbedResponse, error) {
+func (e *sparseEmbedder) SparseEmbed(ctx context.Context, modelName, modelVersion string, req SparseEmbedRequest, opts ...common.CallOption) (*SparseEmbedResponse, error) {
+	ctx, cancel := applyCallOptions(ctx, opts, &req.ID, &req.Parameters)
+	defer cancel()
+
+	req.ID = common.RequestID(ctx, req.ID, e.requestIDs)
+	ctx = common.ContextWithRequestID(ctx, req.ID)
+	ctx, span := common.StartModelSpan(ctx, e.tracer, "SparseEmbedder.SparseEmbed", modelName, modelVersion, len(req.Texts),
+		trace.WithAttributes(common.AttributeRequestID.String(req.ID)))
+	res, err := e.sparseEmbed(ctx, modelName, modelVersion, req)
+	common.EndSpan(span, err)
+
+	return res, err
+}
+
+func (e *sparseEmbedder) sparseEmbed(ctx context.Context, modelName, modelVersion string, req SparseEmbedRequest) (*SparseEmbedResponse, error) {
 	if len(req.Texts) == 0 {
 		return nil, errors.New("texts cannot be empty")
 	}
 
+	// Resolve the tensor names of the model, and the requested auxiliary outputs that it declares.
+	tensors, err := e.tensors.resolve(ctx, modelName, modelVersion, sparseEmbedderTensors, req.Outputs)
+	if err != nil {
+		return nil, err
+	}
+
 	inputs := []common.Input{
 		{
-			Name:     sparseEmbedderInputKey,
+			Name:     tensors.name(sparseEmbedderInputKey),
 			Shape:    []int64{int64(len(req.Texts))},
 			Datatype: sparseEmbedderInputDatatype,
 			Content: common.Content{
@@ -47,8 +83,7 @@ func (e *sparseEmbedder) SparseEmbed(ctx context.Context, modelName, modelVersio
 		},
 	}
 
-	// The embedder model has only one input and one output.
-	outputKeys := []string{sparseEmbedderOutputKey}
+	outputKeys := tensors.outputKeys(sparseEmbedderOutputKey)
 
 	res, err := e.requester.Infer(ctx, common.InferRequest{
 		ID:           req.ID,
@@ -56,12 +91,19 @@ func (e *sparseEmbedder) SparseEmbed(ctx context.Context, modelName, modelVersio
 		ModelVersion: modelVersion,
 		Inputs:       inputs,
 		OutputKeys:   outputKeys,
+		Parameters:   req.Parameters,
 	})
+	if err != nil {
+		e.tensors.invalidateOnError(modelName, modelVersion, err)
+		return nil, err
+	}
+
+	output, err := res.Output(tensors.name(sparseEmbedderOutputKey))
 	if err != nil {
 		return nil, err
 	}
 
-	flat := res.Outputs[0].Content.StringContents
+	flat := output.Content.StringContents
 	if len(flat) == 0 {
 		return nil, errors.New("string matrix is empty")
 	}
@@ -75,13 +117,59 @@ func (e *sparseEmbedder) SparseEmbed(ctx context.Context, modelName, modelVersio
 		embeddings[i] = m
 	}
 
+	aux, err := decodeAuxiliaryOutputs(res, tensors, len(req.Texts))
+	if err != nil {
+		return nil, err
+	}
+
 	return &SparseEmbedResponse{
-		ID:         req.ID,
-		Embeddings: embeddings,
+		ID:          req.ID,
+		Embeddings:  embeddings,
+		TokenCounts: aux.tokenCounts,
+		Truncated:   aux.truncated,
 	}, nil
 }
 
 // Ready implements the Embedder interface. It checks the readiness status of the model.
 func (c *sparseEmbedder) Ready(ctx context.Context, modelName string, modelVersion string) error {
-	return c.requester.Ready(ctx, modelName, modelVersion)
+	err := c.requester.Ready(ctx, modelName, modelVersion)
+	if err != nil {
+		// The model may be reloaded with other tensors before it is ready again.
+		c.tensors.invalidate(modelName, modelVersion)
+	}
+
+	return err
+}
+
+// Model implements the SparseEmbedder interface.
+func (e *sparseEmbedder) Model(modelName, modelVersion string) SparseEmbedderModel {
+	return &sparseEmbedderModel{sparseEmbedder: e, model: fixedModel(modelName, modelVersion)}
+}
+
+// Alias implements the SparseEmbedder interface.
+func (e *sparseEmbedder) Alias(alias string) SparseEmbedderModel {
+	return &sparseEmbedderModel{sparseEmbedder: e, model: aliasedModel(e.models, alias)}
+}
+
+// sparseEmbedderModel is a handle on a model of the sparse embedder.
+type sparseEmbedderModel struct {
+	sparseEmbedder *sparseEmbedder
+	model          modelBinding
+}
+
+var _ SparseEmbedderModel = (*sparseEmbedderModel)(nil)
+
+// SparseEmbed implements the SparseEmbedderModel interface.
+func (m *sparseEmbedderModel) SparseEmbed(ctx context.Context, req SparseEmbedRequest, opts ...common.CallOption) (*SparseEmbedResponse, error) {
+	ref, err := m.model.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.sparseEmbedder.SparseEmbed(ctx, ref.Name, ref.Version, req, opts...)
+}
+
+// Ready implements the SparseEmbedderModel interface. The handles on a routed alias check every version of the route.
+func (m *sparseEmbedderModel) Ready(ctx context.Context) error {
+	return m.model.ready(ctx, m.sparseEmbedder.Ready)
 }
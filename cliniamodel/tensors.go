@@ -0,0 +1,224 @@
+package cliniamodel
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/clinia/models-client-go/cliniamodel/common"
+	"github.com/clinia/models-client-go/cliniamodel/datatype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tensor is a tensor of a model client, identified by its default name.
+type tensor struct {
+	name     string
+	datatype datatype.Datatype
+}
+
+// clientTensors are the tensors of the models of a model client.
+type clientTensors struct {
+	inputs  []tensor
+	outputs []tensor
+	// auxiliary are the auxiliary outputs supported by the client.
+	auxiliary []AuxiliaryOutput
+}
+
+// modelTensors are the tensors of a model version for a request.
+type modelTensors struct {
+	// names maps the default tensor names of the client to the tensor names of the model.
+	names map[string]string
+	// auxiliary are the auxiliary outputs to request, declared by the model among the requested ones.
+	auxiliary []AuxiliaryOutput
+}
+
+// name returns the name of the tensor of the given default name.
+func (t modelTensors) name(tensor string) string {
+	if name, ok := t.names[tensor]; ok {
+		return name
+	}
+
+	return tensor
+}
+
+// outputKeys returns the names of the main output and of the auxiliary outputs to request.
+func (t modelTensors) outputKeys(main string) []string {
+	keys := make([]string, 0, 1+len(t.auxiliary))
+	keys = append(keys, t.name(main))
+	for _, output := range t.auxiliary {
+		keys = append(keys, t.name(string(output)))
+	}
+
+	return keys
+}
+
+// tensorResolver resolves the tensor names of the models of a client from the tensor mapping of the client
+// options and, when needed, from the metadata of the models, which is fetched once per model version and fetched
+// again once the model version is invalidated, e.g. after a reload.
+type tensorResolver struct {
+	requester common.Requester
+	mapping   common.TensorMapping
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	metadata map[string]*common.ModelMetadata
+}
+
+func newTensorResolver(requester common.Requester, mapping common.TensorMapping, logger *slog.Logger) *tensorResolver {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	return &tensorResolver{
+		requester: requester,
+		mapping:   mapping,
+		logger:    logger,
+		metadata:  make(map[string]*common.ModelMetadata),
+	}
+}
+
+// resolve returns the tensors of the model version for a request with the given auxiliary outputs. The metadata
+// of the model is only needed to discover its tensors, or to check which auxiliary outputs it declares. If it
+// cannot be fetched, the request is made with the mapped tensor names and without the auxiliary outputs.
+func (r *tensorResolver) resolve(ctx context.Context, modelName, modelVersion string, tensors clientTensors, requested []AuxiliaryOutput) (modelTensors, error) {
+	resolved := modelTensors{names: make(map[string]string)}
+	for _, t := range slices.Concat(tensors.inputs, tensors.outputs) {
+		resolved.names[t.name] = r.mapping.Name(modelName, t.name)
+	}
+
+	var auxiliary []AuxiliaryOutput
+	for _, output := range requested {
+		if slices.Contains(tensors.auxiliary, output) && !slices.Contains(auxiliary, output) {
+			auxiliary = append(auxiliary, output)
+		}
+	}
+	for _, output := range tensors.auxiliary {
+		resolved.names[string(output)] = r.mapping.Name(modelName, string(output))
+	}
+
+	if !r.mapping.Discover && len(auxiliary) == 0 {
+		return resolved, nil
+	}
+
+	metadata, err := r.modelMetadata(ctx, modelName, modelVersion)
+	if err != nil {
+		if ctx.Err() != nil {
+			return modelTensors{}, err
+		}
+
+		r.logger.LogAttrs(ctx, slog.LevelWarn, "failed to get the model metadata, serving without the auxiliary outputs",
+			slog.String("model_name", modelName),
+			slog.String("model_version", modelVersion),
+			slog.Any("error", err),
+		)
+
+		return resolved, nil
+	}
+
+	if r.mapping.Discover {
+		// The auxiliary outputs are optional: they are never discovered, and the main outputs never match them.
+		reserved := make([]string, 0, len(tensors.auxiliary))
+		for _, output := range tensors.auxiliary {
+			reserved = append(reserved, resolved.name(string(output)))
+		}
+		if err := discoverTensors(resolved.names, tensors.inputs, metadata.Inputs, nil); err != nil {
+			return modelTensors{}, fmt.Errorf("model %s:%s: input %w", modelName, modelVersion, err)
+		}
+		if err := discoverTensors(resolved.names, tensors.outputs, metadata.Outputs, reserved); err != nil {
+			return modelTensors{}, fmt.Errorf("model %s:%s: output %w", modelName, modelVersion, err)
+		}
+	}
+
+	for _, output := range auxiliary {
+		if declares(metadata.Outputs, resolved.name(string(output))) {
+			resolved.auxiliary = append(resolved.auxiliary, output)
+		}
+	}
+
+	return resolved, nil
+}
+
+// modelMetadata returns the metadata of the model version.
+func (r *tensorResolver) modelMetadata(ctx context.Context, modelName, modelVersion string) (*common.ModelMetadata, error) {
+	key := modelName + ":" + modelVersion
+	r.mu.Lock()
+	metadata, ok := r.metadata[key]
+	r.mu.Unlock()
+	if ok {
+		return metadata, nil
+	}
+
+	metadata, err := r.requester.Metadata(ctx, modelName, modelVersion)
+	if err != nil {
+		return nil, fmt.Errorf("get the tensors of the model: %w", err)
+	}
+
+	r.mu.Lock()
+	r.metadata[key] = metadata
+	r.mu.Unlock()
+
+	return metadata, nil
+}
+
+// invalidate forgets the metadata of the model version, so that it is fetched again by the next request.
+func (r *tensorResolver) invalidate(modelName, modelVersion string) {
+	r.mu.Lock()
+	delete(r.metadata, modelName+":"+modelVersion)
+	r.mu.Unlock()
+}
+
+// invalidateOnError invalidates the metadata of the model version if the error of a request to it hints that the
+// model changed: the model is no longer loaded, or it rejected the tensors of the request.
+func (r *tensorResolver) invalidateOnError(modelName, modelVersion string, err error) {
+	switch status.Code(err) {
+	case codes.NotFound, codes.InvalidArgument:
+		r.invalidate(modelName, modelVersion)
+	}
+}
+
+// discoverTensors matches the tensors that the model does not declare under their mapped name with the declared
+// tensor of the same datatype that is neither reserved nor matched by another tensor. The match must be unique:
+// a tensor with several candidates must be mapped explicitly, since the order of declaration is arbitrary.
+func discoverTensors(names map[string]string, tensors []tensor, declared []common.TensorMetadata, reserved []string) error {
+	matched := slices.Clone(reserved)
+	var missing []tensor
+	for _, t := range tensors {
+		if declares(declared, names[t.name]) {
+			matched = append(matched, names[t.name])
+		} else {
+			missing = append(missing, t)
+		}
+	}
+
+	for _, t := range missing {
+		var candidates []string
+		for _, d := range declared {
+			if d.Datatype == t.datatype && !slices.Contains(matched, d.Name) {
+				candidates = append(candidates, d.Name)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			return fmt.Errorf("%s: no %s tensor of the model matches it", names[t.name], t.datatype)
+		case 1:
+			names[t.name] = candidates[0]
+			matched = append(matched, candidates[0])
+		default:
+			return fmt.Errorf("%s: the %s tensors %s of the model match it, map it explicitly", names[t.name], t.datatype, strings.Join(candidates, ", "))
+		}
+	}
+
+	return nil
+}
+
+// declares reports whether the tensors declare the given name.
+func declares(tensors []common.TensorMetadata, name string) bool {
+	return slices.ContainsFunc(tensors, func(t common.TensorMetadata) bool {
+		return t.Name == name
+	})
+}